@@ -0,0 +1,18 @@
+package coati2lang
+
+import "fmt"
+
+// LoxArray es un array del script. Se pasa por referencia, como los sets y
+// las instancias: push, pop y la asignacion por indice se ven desde todas
+// las variables que lo comparten.
+type LoxArray struct {
+	Elements []interface{}
+}
+
+func NewLoxArray(elements []interface{}) *LoxArray {
+	return &LoxArray{Elements: elements}
+}
+
+func (a *LoxArray) String() string {
+	return fmt.Sprint(a.Elements)
+}
@@ -0,0 +1,22 @@
+package coati2lang
+
+import "testing"
+
+func TestArrayAliasing(t *testing.T) {
+	cases := []struct {
+		source string
+		want   string
+	}{
+		{"var a = [1, 2, 3]; a.pop(); var b = a; b.push(9); a.push(7); var result = sprint(a, b);", "[1 2 9 7] [1 2 9 7]"},
+		{"fun addTo(l) { l.push(42); } var a = [1]; addTo(a); var result = sprint(a);", "[1 42]"},
+		{"var items = [1]; fun getItems() { return items; } getItems().push(5); var result = sprint(items);", "[1 5]"},
+		{"var m = {list: [1]}; m.list.push(2); var result = sprint(m.list);", "[1 2]"},
+		{"var a = [1]; var b = a; b[2] = 3; var result = sprint(a);", "[1 <nil> 3]"},
+		{"var a = [1]; var b = clone(a); b.push(2); var result = sprint(a, b);", "[1] [1 2]"},
+	}
+	for _, c := range cases {
+		if got := runSandboxed(t, c.source, nil); got != c.want {
+			t.Errorf("%s\ngot  %v\nwant %s", c.source, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package coati2lang
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Tamaño aproximado en bytes de cada elemento de un array o set y de cada
+// entrada de un mapa, para el limite de memoria.
+const (
+	ELEMENT_SIZE = 16
+	ENTRY_SIZE   = 32
+)
+
+var (
+	ErrStepLimit   = errors.New("step limit exceeded")
+	ErrMemoryLimit = errors.New("memory limit exceeded")
+)
+
+// budget lleva la cuenta de pasos y memoria de una ejecucion. Lo comparten
+// el interprete y sus forks (generadores).
+type budget struct {
+	ctx           context.Context
+	steps         int64
+	maxSteps      int64
+	allocated     int64
+	maxAllocation int64
+}
+
+// isBudgetError indica los errores que cortan la ejecucion: catch no los
+// atrapa, para que un script no pueda esquivar sus limites.
+func isBudgetError(err *RuntimeError) bool {
+	switch err.Kind {
+	case STEP_LIMIT_ERROR, MEMORY_LIMIT_ERROR, CANCELLED_ERROR:
+		return true
+	}
+	return false
+}
+
+// step cuenta un nodo evaluado y, cada tanto, revisa si el contexto fue
+// cancelado.
+func (i *Interpreter) step() {
+	b := i.budget
+	if b == nil {
+		return
+	}
+	b.steps++
+	if b.maxSteps > 0 && b.steps > b.maxSteps {
+		panic(&RuntimeError{Kind: STEP_LIMIT_ERROR, Message: fmt.Sprintf("Step limit of %d exceeded.", b.maxSteps), Cause: ErrStepLimit})
+	}
+	if b.steps%256 == 0 {
+		i.checkCancelled()
+	}
+}
+
+// poll revisa, cada tanto, si el contexto fue cancelado. Lo llaman las
+// nativas con bucles que dependen del tamaño de la entrada, que no pasan
+// por step.
+func (i *Interpreter) poll(iteration int) {
+	if iteration%1024 == 0 {
+		i.checkCancelled()
+	}
+}
+
+func (i *Interpreter) checkCancelled() {
+	if i.budget == nil {
+		return
+	}
+	if err := i.budget.ctx.Err(); err != nil {
+		panic(&RuntimeError{Kind: CANCELLED_ERROR, Message: fmt.Sprintf("Execution cancelled: %s.", err), Cause: err})
+	}
+}
+
+// allocate suma size bytes a la memoria usada. La cuenta es acumulada: no
+// descuenta lo que libera el GC.
+func (i *Interpreter) allocate(token Token, size int64) {
+	b := i.budget
+	if b == nil || b.maxAllocation <= 0 {
+		return
+	}
+	if size > b.maxAllocation-b.allocated {
+		panic(&RuntimeError{Token: token, Kind: MEMORY_LIMIT_ERROR, Message: fmt.Sprintf("Memory limit of %d bytes exceeded.", b.maxAllocation), Cause: ErrMemoryLimit})
+	}
+	b.allocated += size
+}
+
+// repeatSize recorta count a un entero no negativo y calcula cuantos bytes
+// ocupan count copias de size. Si el total no entra en un int64 devuelve
+// math.MaxInt64, que ningun limite de memoria acepta.
+func repeatSize(size int64, count float64) (int, int64) {
+	if !(count >= 1) {
+		return 0, 0
+	}
+	if size == 0 {
+		return int(math.Min(count, math.MaxInt32)), 0
+	}
+	if count >= float64(math.MaxInt64/size) {
+		return 0, math.MaxInt64
+	}
+	return int(count), int64(count) * size
+}
+
+// repeat devuelve text repetido count veces, cobrando el resultado antes de
+// construirlo.
+func (i *Interpreter) repeat(token Token, text string, count float64) string {
+	n, size := repeatSize(int64(len(text)), count)
+	i.allocate(token, size)
+	if size == math.MaxInt64 {
+		raise(token, VALUE_ERROR, "Can't repeat a string %v times.", count)
+	}
+	return strings.Repeat(text, n)
+}
+
+// charge cuenta el tamaño de un valor recien creado y lo devuelve.
+func (i *Interpreter) charge(token Token, value interface{}) interface{} {
+	i.allocate(token, sizeOf(value))
+	return value
+}
+
+func sizeOf(value interface{}) int64 {
+	switch v := value.(type) {
+	case string:
+		return int64(len(v))
+	case *LoxArray:
+		return int64(len(v.Elements)) * ELEMENT_SIZE
+	case Tuple:
+		return int64(len(v)) * ELEMENT_SIZE
+	case map[interface{}]interface{}:
+		return int64(len(v)) * ENTRY_SIZE
+	case *LoxSet:
+		return int64(v.Len()) * ELEMENT_SIZE
+	}
+	return 0
+}
@@ -0,0 +1,136 @@
+package coati2lang
+
+type LoxClass struct {
+	Name       string
+	Superclass *LoxClass
+	Methods    map[string]Function
+}
+
+type LoxInstance struct {
+	Class  *LoxClass
+	Fields map[string]interface{}
+}
+
+// boundMethod es un metodo ligado a su instancia: conserva el this aunque
+// se guarde en una variable y se llame despues.
+type boundMethod struct {
+	function      Function
+	this          interface{}
+	isInitializer bool
+}
+
+func (c *LoxClass) String() string {
+	return c.Name
+}
+
+func (c *LoxClass) FindMethod(name string) (Function, bool) {
+	if method, ok := c.Methods[name]; ok {
+		return method, true
+	}
+	if c.Superclass != nil {
+		return c.Superclass.FindMethod(name)
+	}
+	return Function{}, false
+}
+
+func (c *LoxClass) bind(name string, this interface{}) (boundMethod, bool) {
+	method, ok := c.FindMethod(name)
+	if !ok {
+		return boundMethod{}, false
+	}
+	return boundMethod{function: method, this: this, isInitializer: name == "init"}, true
+}
+
+func (c *LoxClass) Call(interpreter *Interpreter, arguments []interface{}, this interface{}) interface{} {
+	instance := &LoxInstance{Class: c, Fields: make(map[string]interface{})}
+	if initializer, ok := c.bind("init", instance); ok {
+		initializer.Call(interpreter, arguments, nil)
+	}
+	return instance
+}
+
+func (c *LoxClass) Arity() int {
+	if initializer, ok := c.FindMethod("init"); ok {
+		return initializer.Arity()
+	}
+	return 0
+}
+
+func (o *LoxInstance) String() string {
+	return o.Class.Name + " instance"
+}
+
+func (o *LoxInstance) Get(name string) (interface{}, bool) {
+	if value, ok := o.Fields[name]; ok {
+		return value, true
+	}
+	if method, ok := o.Class.bind(name, o); ok {
+		return method, true
+	}
+	return nil, false
+}
+
+func (o *LoxInstance) Set(name string, value interface{}) {
+	o.Fields[name] = value
+}
+
+func (m boundMethod) String() string {
+	return "<fn " + m.function.Name.Lexeme + ">"
+}
+
+func (m boundMethod) Call(interpreter *Interpreter, arguments []interface{}, this interface{}) interface{} {
+	value := m.function.Call(interpreter, arguments, m.this)
+	if m.isInitializer {
+		return m.this
+	}
+	return value
+}
+
+func (m boundMethod) Arity() int {
+	return m.function.Arity()
+}
+
+func (i *Interpreter) VisitClassStmt(stmt Class) interface{} {
+	var superclass *LoxClass
+	if stmt.Superclass != nil {
+		value := i.evaluate(*stmt.Superclass)
+		class, ok := value.(*LoxClass)
+		if !ok {
+			raise(stmt.Superclass.Name, TYPE_ERROR, "Superclass must be a class.")
+		}
+		superclass = class
+	}
+
+	i.enviroment.Define(stmt.Name.Lexeme, nil)
+
+	enviroment := i.enviroment
+	if superclass != nil {
+		enviroment = NewEnviroment(i.enviroment)
+		enviroment.Define("super", superclass)
+	}
+
+	methods := make(map[string]Function)
+	for _, method := range stmt.Methods {
+		methods[method.Name.Lexeme] = Function{
+			Name:        method.Name,
+			Parameters:  method.Parameters,
+			Body:        method.Body,
+			Closure:     enviroment,
+			IsGenerator: method.IsGenerator,
+		}
+	}
+
+	i.enviroment.Define(stmt.Name.Lexeme, &LoxClass{Name: stmt.Name.Lexeme, Superclass: superclass, Methods: methods})
+	return nil
+}
+
+func (i *Interpreter) VisitSuperExpr(expr Super) interface{} {
+	value, _ := i.lookUpVariable(expr.Keyword)
+	superclass := value.(*LoxClass)
+	this, _ := i.enviroment.Get("this")
+	method, ok := superclass.bind(expr.Method.Lexeme, this)
+	if !ok {
+		raiseHint(expr.Method, NAME_ERROR, suggest(expr.Method.Lexeme, superclass.methodNames()), "Undefined property '%s'.", expr.Method.Lexeme)
+	}
+	return method
+}
@@ -0,0 +1,145 @@
+package coati2lang
+
+import (
+	"reflect"
+)
+
+// isEqual compara por estructura arrays, tuplas, mapas y sets; funciones y
+// objetos se comparan por identidad. Nunca falla por tipos no comparables.
+func (i *Interpreter) isEqual(a interface{}, b interface{}) bool {
+	return i.equal(a, b, comparing{})
+}
+
+// comparing son los pares de arrays o mapas que ya se estan comparando mas
+// arriba; volver a encontrar uno (un array que se contiene a si mismo) no
+// agrega diferencias.
+type comparing map[[2]uintptr]bool
+
+// enter marca el par left, right; devuelve false si ya estaba marcado o si
+// son el mismo valor, y entonces no hace falta recorrerlos.
+func (c comparing) enter(left interface{}, right interface{}) bool {
+	pair := [2]uintptr{reflect.ValueOf(left).Pointer(), reflect.ValueOf(right).Pointer()}
+	if pair[0] == pair[1] || c[pair] {
+		return false
+	}
+	c[pair] = true
+	return true
+}
+
+func (i *Interpreter) equal(a interface{}, b interface{}, seen comparing) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	switch left := thaw(a).(type) {
+	case float64, int, int64:
+		if !isNumber(b) {
+			return false
+		}
+		return toFloat(left) == toFloat(b)
+	case []interface{}:
+		right, ok := thaw(b).([]interface{})
+		if !ok || len(left) != len(right) {
+			return false
+		}
+		if len(left) == 0 || !seen.enter(left, right) {
+			return true
+		}
+		for index := range left {
+			if !i.equal(left[index], right[index], seen) {
+				return false
+			}
+		}
+		return true
+	case map[interface{}]interface{}:
+		right, ok := thaw(b).(map[interface{}]interface{})
+		if !ok || len(left) != len(right) {
+			return false
+		}
+		if !seen.enter(left, right) {
+			return true
+		}
+		for key, value := range left {
+			other, ok := right[key]
+			if !ok || !i.equal(value, other, seen) {
+				return false
+			}
+		}
+		return true
+	case *LoxSet:
+		right, ok := b.(*LoxSet)
+		if !ok || left.Len() != right.Len() {
+			return false
+		}
+		for _, item := range left.order {
+			if !right.Has(item) {
+				return false
+			}
+		}
+		return true
+	case Function:
+		right, ok := b.(Function)
+		return ok && sameFunction(left, right)
+	case boundMethod:
+		right, ok := b.(boundMethod)
+		return ok && sameFunction(left.function, right.function) && i.equal(left.this, right.this, seen)
+	}
+
+	if reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.TypeOf(a).Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// sameFunction: la misma declaracion cerrada sobre el mismo entorno.
+func sameFunction(a Function, b Function) bool {
+	return a.Name.Lexeme == b.Name.Lexeme && a.Name.Line == b.Name.Line && a.Name.Column == b.Name.Column && a.Closure == b.Closure
+}
+
+func isNumber(value interface{}) bool {
+	switch value.(type) {
+	case float64, int, int64:
+		return true
+	}
+	return false
+}
+
+// compareValues implementa <, <=, > y >= para numeros y strings (orden
+// lexicografico). Otros tipos o tipos mezclados son un error.
+func (i *Interpreter) compareValues(operator Token, left interface{}, right interface{}) bool {
+	var result int
+	switch {
+	case isNumber(left) && isNumber(right):
+		a, b := toFloat(left), toFloat(right)
+		if a < b {
+			result = -1
+		} else if a > b {
+			result = 1
+		} else if a != b {
+			// NaN no es menor, mayor ni igual a nada.
+			return false
+		}
+	default:
+		a, leftIsString := left.(string)
+		b, rightIsString := right.(string)
+		if !leftIsString || !rightIsString {
+			raise(operator, TYPE_ERROR, "Can't compare '%s' and '%s' with '%s'.", typeName(left), typeName(right), operator.Lexeme)
+		}
+		if a < b {
+			result = -1
+		} else if a > b {
+			result = 1
+		}
+	}
+
+	switch operator.Type {
+	case LESS:
+		return result < 0
+	case LESS_EQUAL:
+		return result <= 0
+	case GREATER:
+		return result > 0
+	default:
+		return result >= 0
+	}
+}
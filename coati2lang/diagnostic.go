@@ -0,0 +1,222 @@
+package coati2lang
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+type Severity string
+
+const (
+	SEVERITY_ERROR   Severity = "error"
+	SEVERITY_WARNING Severity = "warning"
+)
+
+type Position struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// Diagnostic es la forma comun de todos los errores (sintaxis, resolver y
+// ejecucion) para mostrarlos como texto, JSON o SARIF.
+type Diagnostic struct {
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	Message  string   `json:"message"`
+	File     string   `json:"file"`
+	Range    Range    `json:"range"`
+	Hints    []string `json:"hints,omitempty"`
+	// Stack es la pila de Lox de los errores de ejecucion.
+	Stack []StackFrame `json:"stack,omitempty"`
+
+	text string
+}
+
+func (d Diagnostic) String() string {
+	if d.text != "" {
+		return d.text
+	}
+	return fmt.Sprintf("[line %d] %s: %s", d.Range.Start.Line, d.Severity, d.Message)
+}
+
+func tokenRange(line, column, length int) Range {
+	start := Position{Line: line, Column: column}
+	end := start
+	if column > 0 {
+		end.Column = column + length
+	}
+	return Range{Start: start, End: end}
+}
+
+// NewDiagnostic convierte un error del interprete en un Diagnostic. Los
+// errores en tiempo de ejecucion llevan la pila de Lox en Stack.
+func NewDiagnostic(file string, err error) Diagnostic {
+	diagnostic := Diagnostic{Severity: SEVERITY_ERROR, File: file, Message: err.Error(), Code: "Error", text: err.Error()}
+	switch e := err.(type) {
+	case SyntaxError:
+		diagnostic.Code = "SyntaxError"
+		diagnostic.Message = e.Message
+		diagnostic.Range = tokenRange(e.Line, e.Column, e.Length)
+	case ResolveError:
+		diagnostic.Code = "ResolveError"
+		diagnostic.Message = e.Message
+		diagnostic.Range = tokenRange(e.Token.Line, e.Token.Column, utf8.RuneCountInString(e.Token.Lexeme))
+		if e.Hint != "" {
+			diagnostic.Hints = []string{e.Hint}
+		}
+	case *RuntimeError:
+		diagnostic.Code = string(e.Kind)
+		diagnostic.Message = e.Message
+		diagnostic.Range = tokenRange(e.Token.Line, e.Token.Column, utf8.RuneCountInString(e.Token.Lexeme))
+		if e.Hint != "" {
+			diagnostic.Hints = append(diagnostic.Hints, e.Hint)
+		}
+		diagnostic.Stack = e.Frames()
+		diagnostic.text = e.Error() + "\n" + strings.TrimRight(e.StackTrace(), "\n")
+	}
+	return diagnostic
+}
+
+// WriteDiagnostics escribe los diagnosticos en el formato pedido: "text"
+// (el formato de siempre), "json" o "sarif".
+func WriteDiagnostics(w io.Writer, format string, diagnostics []Diagnostic) error {
+	switch format {
+	case "", "text":
+		for _, diagnostic := range diagnostics {
+			fmt.Fprintln(w, diagnostic)
+		}
+		return nil
+	case "json", "sarif":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		if format == "sarif" {
+			return encoder.Encode(toSarif(diagnostics))
+		}
+		return encoder.Encode(diagnostics)
+	}
+	return fmt.Errorf("unknown diagnostics format '%s'", format)
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool       sarifTool     `json:"tool"`
+	ColumnKind string        `json:"columnKind"`
+	Results    []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID           string                 `json:"ruleId"`
+	Level            string                 `json:"level"`
+	Message          sarifMessage           `json:"message"`
+	Locations        []sarifLocation        `json:"locations"`
+	RelatedLocations []sarifLocation        `json:"relatedLocations,omitempty"`
+	Properties       map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	ID               int                   `json:"id,omitempty"`
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	Message          *sarifMessage         `json:"message,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+func toSarif(diagnostics []Diagnostic) sarifLog {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "go-r2lox",
+			InformationURI: "https://github.com/arturoeanton/go-r2lox",
+			Rules:          []sarifRule{},
+		}},
+		// Las columnas se cuentan en runas, no en unidades UTF-16.
+		ColumnKind: "unicodeCodePoints",
+		Results:    []sarifResult{},
+	}
+	rules := make(map[string]bool)
+	for _, diagnostic := range diagnostics {
+		if !rules[diagnostic.Code] {
+			rules[diagnostic.Code] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: diagnostic.Code})
+		}
+		location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: diagnostic.File}}}
+		if diagnostic.Range.Start.Line > 0 {
+			location.PhysicalLocation.Region = &sarifRegion{
+				StartLine:   diagnostic.Range.Start.Line,
+				StartColumn: diagnostic.Range.Start.Column,
+				EndColumn:   diagnostic.Range.End.Column,
+			}
+		}
+		result := sarifResult{
+			RuleID:    diagnostic.Code,
+			Level:     string(diagnostic.Severity),
+			Message:   sarifMessage{Text: diagnostic.Message},
+			Locations: []sarifLocation{location},
+		}
+		// La pila de Lox va como ubicaciones relacionadas, de adentro hacia afuera.
+		for index, frame := range diagnostic.Stack {
+			related := sarifLocation{
+				ID:               index + 1,
+				PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: diagnostic.File}},
+				Message:          &sarifMessage{Text: "at " + frame.Name},
+			}
+			if frame.Line > 0 {
+				related.PhysicalLocation.Region = &sarifRegion{StartLine: frame.Line}
+			}
+			result.RelatedLocations = append(result.RelatedLocations, related)
+		}
+		if len(diagnostic.Hints) > 0 {
+			result.Properties = map[string]interface{}{"hints": diagnostic.Hints}
+		}
+		run.Results = append(run.Results, result)
+	}
+	return sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []sarifRun{run},
+	}
+}
@@ -0,0 +1,94 @@
+package coati2lang
+
+type LoxEnum struct {
+	Name    string
+	Members []*LoxEnumMember
+}
+
+type LoxEnumMember struct {
+	Enum    *LoxEnum
+	Name    string
+	Ordinal int
+	Value   interface{}
+}
+
+func (e *LoxEnum) String() string {
+	return "<enum " + e.Name + ">"
+}
+
+func (e *LoxEnum) Lookup(name string) (*LoxEnumMember, bool) {
+	for _, member := range e.Members {
+		if member.Name == name {
+			return member, true
+		}
+	}
+	return nil, false
+}
+
+func (e *LoxEnum) Get(name string) (interface{}, bool) {
+	if member, ok := e.Lookup(name); ok {
+		return member, true
+	}
+	switch name {
+	case "values", "valueOf":
+		return enumMethod{enum: e, name: name}, true
+	}
+	return nil, false
+}
+
+func (m *LoxEnumMember) String() string {
+	return m.Enum.Name + "." + m.Name
+}
+
+func (m *LoxEnumMember) Get(name string) (interface{}, bool) {
+	switch name {
+	case "name":
+		return m.Name, true
+	case "value":
+		return m.Value, true
+	case "ordinal":
+		return float64(m.Ordinal), true
+	}
+	return nil, false
+}
+
+type enumMethod struct {
+	enum *LoxEnum
+	name string
+}
+
+func (m enumMethod) Call(interpreter *Interpreter, arguments []interface{}, this interface{}) interface{} {
+	if m.name == "valueOf" {
+		if name, ok := arguments[0].(string); ok {
+			if member, ok := m.enum.Lookup(name); ok {
+				return member
+			}
+		}
+		return nil
+	}
+	values := make([]interface{}, len(m.enum.Members))
+	for index, member := range m.enum.Members {
+		values[index] = member
+	}
+	return NewLoxArray(values)
+}
+
+func (m enumMethod) Arity() int {
+	if m.name == "valueOf" {
+		return 1
+	}
+	return 0
+}
+
+func (i *Interpreter) VisitEnumStmt(stmt Enum) interface{} {
+	enum := &LoxEnum{Name: stmt.Name.Lexeme}
+	for ordinal, item := range stmt.Members {
+		member := &LoxEnumMember{Enum: enum, Name: item.Name.Lexeme, Ordinal: ordinal, Value: float64(ordinal)}
+		if item.Value != nil {
+			member.Value = i.full_evaluate(item.Value)
+		}
+		enum.Members = append(enum.Members, member)
+	}
+	i.enviroment.Define(stmt.Name.Lexeme, enum)
+	return nil
+}
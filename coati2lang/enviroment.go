@@ -40,3 +40,20 @@ func (e *Enviroment) Assign(name string, value interface{}) {
 	}
 
 }
+
+func (e *Enviroment) ancestor(distance int) *Enviroment {
+	enviroment := e
+	for i := 0; i < distance && enviroment.Enclosing != nil; i++ {
+		enviroment = enviroment.Enclosing
+	}
+	return enviroment
+}
+
+func (e *Enviroment) GetAt(distance int, name string) (interface{}, bool) {
+	value, ok := e.ancestor(distance).Values[name]
+	return value, ok
+}
+
+func (e *Enviroment) AssignAt(distance int, name string, value interface{}) {
+	e.ancestor(distance).Values[name] = value
+}
@@ -17,6 +17,15 @@ type Visitor interface {
 	VisitAssignExpr(stmt Assign) interface{}
 	VisitLogicalExpr(expr Logical) interface{}
 	VisitCallExpr(expr Call) interface{}
+	VisitGetExpr(expr Get) interface{}
+	VisitIndexExpr(expr Index) interface{}
+	VisitSetExpr(expr Set) interface{}
+	VisitCompoundAssignExpr(expr CompoundAssign) interface{}
+	VisitIncrementExpr(expr Increment) interface{}
+	VisitMultiAssignExpr(expr MultiAssign) interface{}
+	VisitTupleExpr(expr TupleLiteral) interface{}
+	VisitSetLiteralExpr(expr SetLiteral) interface{}
+	VisitMultiVarStmt(stmt MultiVar) interface{}
 
 	VisitExpressionStmt(stmt Expression) interface{}
 	VisitVar(stmt Var) interface{}
@@ -27,6 +36,16 @@ type Visitor interface {
 	VisitWhileStmt(stmt While) interface{}
 	VisitFunctionStmt(stmt Function) interface{}
 	VisitReturnStmt(stmt Return) interface{}
+	VisitYieldStmt(stmt Yield) interface{}
+	VisitForInStmt(stmt ForIn) interface{}
+	VisitEnumStmt(stmt Enum) interface{}
+	VisitMatchExpr(expr Match) interface{}
+	VisitExtendStmt(stmt Extend) interface{}
+	VisitClassStmt(stmt Class) interface{}
+	VisitSuperExpr(expr Super) interface{}
+	VisitPrintStmt(stmt PrintStmt) interface{}
+	VisitTryStmt(stmt Try) interface{}
+	VisitThrowStmt(stmt Throw) interface{}
 }
 
 type Binary struct {
@@ -48,6 +67,7 @@ func (g Grouping) AcceptExpr(visitor Visitor) interface{} {
 }
 
 type GroupingABS struct {
+	Pipe       Token
 	Expression Expr
 }
 
@@ -92,7 +112,7 @@ func (c Condition) AcceptStmt(visitor Visitor) interface{} {
 	Expression Expr
 }
 
-func (p Print) AcceptStmt(visitor Visitor) interface{} {
+func (p PrintStmt) AcceptStmt(visitor Visitor) interface{} {
 	return visitor.VisitPrint(p)
 }*/
 
@@ -102,7 +122,6 @@ type Var struct {
 	InitializerArray []Expr
 	InitializerMap   []ItemVar
 	InitializerFx    Stmt
-	Selectors        [][]Expr
 	Sub              bool
 	SizeArrayInit    int
 }
@@ -132,18 +151,132 @@ func (a Assign) AcceptExpr(visitor Visitor) interface{} {
 	return visitor.VisitAssignExpr(a)
 }
 
+type TupleLiteral struct {
+	Paren    Token
+	Elements []Expr
+}
+
+func (t TupleLiteral) AcceptExpr(visitor Visitor) interface{} {
+	return visitor.VisitTupleExpr(t)
+}
+
+type SetLiteral struct {
+	Brace    Token
+	Elements []Expr
+}
+
+func (s SetLiteral) AcceptExpr(visitor Visitor) interface{} {
+	return visitor.VisitSetLiteralExpr(s)
+}
+
+// MultiAssign es la asignacion en paralelo a, b = b, a.
+type MultiAssign struct {
+	Targets []Expr
+	Equals  Token
+	Value   Expr
+}
+
+func (m MultiAssign) AcceptExpr(visitor Visitor) interface{} {
+	return visitor.VisitMultiAssignExpr(m)
+}
+
+// MultiVar es var a, b = f();
+type MultiVar struct {
+	Names []Token
+	Value Expr
+}
+
+func (m MultiVar) AcceptStmt(visitor Visitor) interface{} {
+	return visitor.VisitMultiVarStmt(m)
+}
+
 type Call struct {
 	Callee    Expr
 	Paren     Token
 	Arguments []Expr
-	This      Var
-	SubCall   *Call
+	Optional  bool
 }
 
 func (c Call) AcceptExpr(visitor Visitor) interface{} {
 	return visitor.VisitCallExpr(c)
 }
 
+type Get struct {
+	Object   Expr
+	Name     Token
+	Optional bool
+}
+
+func (g Get) AcceptExpr(visitor Visitor) interface{} {
+	return visitor.VisitGetExpr(g)
+}
+
+type Index struct {
+	Object   Expr
+	Bracket  Token
+	Indexes  []Expr
+	Optional bool
+}
+
+func (x Index) AcceptExpr(visitor Visitor) interface{} {
+	return visitor.VisitIndexExpr(x)
+}
+
+type Set struct {
+	Object    Expr
+	Equals    Token
+	Selectors [][]Expr
+	Value     Expr
+}
+
+func (s Set) AcceptExpr(visitor Visitor) interface{} {
+	return visitor.VisitSetExpr(s)
+}
+
+// CompoundAssign es target op= value; Operator es el operador binario (+, -, ...).
+type CompoundAssign struct {
+	Target   Expr
+	Operator Token
+	Value    Expr
+}
+
+func (c CompoundAssign) AcceptExpr(visitor Visitor) interface{} {
+	return visitor.VisitCompoundAssignExpr(c)
+}
+
+type Increment struct {
+	Target   Expr
+	Operator Token
+	Prefix   bool
+}
+
+func (i Increment) AcceptExpr(visitor Visitor) interface{} {
+	return visitor.VisitIncrementExpr(i)
+}
+
+// flattenTarget convierte una cadena a.b[i].c en su raiz y la ruta de
+// selectores que usa setByPath. Falla si la cadena no es asignable.
+func flattenTarget(expr Expr) (Expr, [][]Expr, bool) {
+	switch target := expr.(type) {
+	case Get:
+		root, selectors, ok := flattenTarget(target.Object)
+		if !ok || target.Optional {
+			return nil, nil, false
+		}
+		return root, append(selectors, []Expr{Literal{Value: target.Name.Lexeme}}), true
+	case Index:
+		root, selectors, ok := flattenTarget(target.Object)
+		if !ok || target.Optional {
+			return nil, nil, false
+		}
+		return root, append(selectors, target.Indexes), true
+	case Var:
+		return target, [][]Expr{}, true
+	default:
+		return expr, [][]Expr{}, true
+	}
+}
+
 type Super struct {
 	Keyword Token
 
@@ -151,20 +284,30 @@ type Super struct {
 }
 
 func (s Super) AcceptExpr(visitor Visitor) interface{} {
-	return nil
+	return visitor.VisitSuperExpr(s)
 }
 
 type Class struct {
 	Name       Token
 	Methods    []Function
-	Superclass Var
+	Superclass *Var
 }
 
-func (c Class) AcceptExpr(visitor Visitor) interface{} {
-	return nil
+func (c Class) AcceptStmt(visitor Visitor) interface{} {
+	return visitor.VisitClassStmt(c)
+}
+
+type PrintStmt struct {
+	Keyword    Token
+	Expression Expr
+}
+
+func (p PrintStmt) AcceptStmt(visitor Visitor) interface{} {
+	return visitor.VisitPrintStmt(p)
 }
 
 type If struct {
+	Keyword    Token
 	Condition  Expr
 	ThenBranch Stmt
 	ElseBranch Stmt
@@ -175,6 +318,7 @@ func (i If) AcceptStmt(visitor Visitor) interface{} {
 }
 
 type While struct {
+	Keyword   Token
 	Condition Expr
 	Body      Stmt
 }
@@ -201,6 +345,118 @@ func (r Return) AcceptStmt(visitor Visitor) interface{} {
 	return visitor.VisitReturnStmt(r)
 }
 
+type Yield struct {
+	Keyword Token
+	Value   Expr
+}
+
+func (y Yield) AcceptStmt(visitor Visitor) interface{} {
+	return visitor.VisitYieldStmt(y)
+}
+
+// Try guarda los bloques de try/catch/finally; Catch y Finally son nil
+// cuando no estan. CatchName es opcional: catch { ... }.
+type Try struct {
+	Keyword   Token
+	Body      []Stmt
+	CatchName *Token
+	Catch     []Stmt
+	Finally   []Stmt
+}
+
+func (t Try) AcceptStmt(visitor Visitor) interface{} {
+	return visitor.VisitTryStmt(t)
+}
+
+type Throw struct {
+	Keyword Token
+	Value   Expr
+}
+
+func (t Throw) AcceptStmt(visitor Visitor) interface{} {
+	return visitor.VisitThrowStmt(t)
+}
+
+type ForIn struct {
+	Name     Token
+	Iterable Expr
+	Body     Stmt
+}
+
+func (f ForIn) AcceptStmt(visitor Visitor) interface{} {
+	return visitor.VisitForInStmt(f)
+}
+
+type Enum struct {
+	Name    Token
+	Members []EnumItem
+}
+
+type EnumItem struct {
+	Name  Token
+	Value Expr
+}
+
+func (e Enum) AcceptStmt(visitor Visitor) interface{} {
+	return visitor.VisitEnumStmt(e)
+}
+
+type Match struct {
+	Keyword Token
+	Subject Expr
+	Arms    []MatchArm
+}
+
+type MatchArm struct {
+	Pattern Pattern
+	Guard   Expr
+	Body    Expr
+}
+
+func (m Match) AcceptExpr(visitor Visitor) interface{} {
+	return visitor.VisitMatchExpr(m)
+}
+
+type Pattern interface{}
+
+type WildcardPattern struct{}
+
+type LiteralPattern struct {
+	Value interface{}
+}
+
+type ValuePattern struct {
+	Value Expr
+}
+
+type BindingPattern struct {
+	Name Token
+}
+
+type ArrayPattern struct {
+	Elements []Pattern
+	Rest     *Token
+}
+
+type MapPattern struct {
+	Entries []MapPatternEntry
+}
+
+type MapPatternEntry struct {
+	Key     interface{}
+	Pattern Pattern
+}
+
+type Extend struct {
+	Keyword Token
+	Type    Token
+	Methods []Function
+}
+
+func (e Extend) AcceptStmt(visitor Visitor) interface{} {
+	return visitor.VisitExtendStmt(e)
+}
+
 type Break struct {
 	Keyword Token
 }
@@ -229,10 +485,11 @@ func (l Logical) AcceptExpr(visitor Visitor) interface{} {
 }
 
 type Function struct {
-	Name       Token
-	Parameters []Token
-	Body       []Stmt
-	Closure    *Enviroment
+	Name        Token
+	Parameters  []Token
+	Body        []Stmt
+	Closure     *Enviroment
+	IsGenerator bool
 }
 
 func (f Function) AcceptStmt(visitor Visitor) interface{} {
@@ -240,11 +497,22 @@ func (f Function) AcceptStmt(visitor Visitor) interface{} {
 }
 
 func (f Function) Call(i *Interpreter, arguments []interface{}, this interface{}) interface{} {
+	if f.IsGenerator {
+		return NewGenerator(i, f, arguments, this)
+	}
+	return f.call(i, arguments, this)
+}
+
+func (f Function) call(i *Interpreter, arguments []interface{}, this interface{}) interface{} {
 
 	enviroment := NewEnviroment(f.Closure)
 	for i, param := range f.Parameters {
 		enviroment.Define(param.Lexeme, arguments[i])
 	}
+	if this == nil && f.Closure != nil {
+		// Las funciones anidadas en un metodo ven el this del metodo.
+		this, _ = f.Closure.Get("this")
+	}
 	enviroment.Define("this", this)
 
 	var value interface{}
@@ -0,0 +1,54 @@
+package coati2lang
+
+import (
+	"unicode/utf8"
+)
+
+func init() {
+	GlobalFx["ord"] = Ord{}
+	GlobalFx["chr"] = Chr{}
+}
+
+var ordSignature = sig("(char: string) -> number", "Unicode code point of a single character.")
+
+type Ord struct {
+}
+
+// Call devuelve el codigo Unicode de un string de un solo caracter.
+func (c Ord) Call(interpreter *Interpreter, arguments []interface{}, this interface{}) interface{} {
+	text, ok := arguments[0].(string)
+	if !ok || utf8.RuneCountInString(text) != 1 {
+		raise(Token{}, VALUE_ERROR, "ord: expected a single character, got '%v'.", arguments[0])
+	}
+	char, _ := utf8.DecodeRuneInString(text)
+	return float64(char)
+}
+
+func (c Ord) Arity() int {
+	return ordSignature.Arity()
+}
+
+func (c Ord) Signature() *Signature {
+	return ordSignature
+}
+
+var chrSignature = sig("(code: number) -> string", "Character for a Unicode code point.")
+
+type Chr struct {
+}
+
+func (c Chr) Call(interpreter *Interpreter, arguments []interface{}, this interface{}) interface{} {
+	code, ok := arguments[0].(float64)
+	if !ok || code < 0 || code > utf8.MaxRune || code != float64(int(code)) {
+		raise(Token{}, VALUE_ERROR, "chr: invalid code point '%v'.", arguments[0])
+	}
+	return string(rune(int(code)))
+}
+
+func (c Chr) Arity() int {
+	return chrSignature.Arity()
+}
+
+func (c Chr) Signature() *Signature {
+	return chrSignature
+}
@@ -8,33 +8,45 @@ func init() {
 	GlobalFx["clone"] = Clone{}
 }
 
+var cloneSignature = sig("(value: array|tuple|map|set) -> array|map|set", "Deep copy; tuples and frozen maps become mutable.")
+
 type Clone struct {
 }
 
 func (c Clone) Call(interpreter *Interpreter, arguments []interface{}, this interface{}) interface{} {
 	var first interface{} = arguments[0]
-	switch cast_element := first.(type) {
+	switch cast_element := thaw(first).(type) {
 	case []interface{}:
-		return cloneArray(cast_element)
+		return NewLoxArray(cloneArray(cast_element))
 	case map[interface{}]interface{}:
 		return cloneMap(cast_element)
+	case *LoxSet:
+		return cast_element.Clone()
 	default:
 		return fmt.Errorf("clone: type %T not supported", first)
 	}
 }
 
 func (c Clone) Arity() int {
-	return 1
+	return cloneSignature.Arity()
+}
+
+func (c Clone) Signature() *Signature {
+	return cloneSignature
 }
 
 func cloneArray(array []interface{}) []interface{} {
 	clone := make([]interface{}, len(array))
 	for i, v := range array {
-		switch cast_element := v.(type) {
+		// thaw: las tuplas y los mapas congelados anidados tambien se copian
+		// como valores mutables.
+		switch cast_element := thaw(v).(type) {
 		case []interface{}:
-			clone[i] = cloneArray(cast_element)
+			clone[i] = NewLoxArray(cloneArray(cast_element))
 		case map[interface{}]interface{}:
 			clone[i] = cloneMap(cast_element)
+		case *LoxSet:
+			clone[i] = cast_element.Clone()
 		default:
 			clone[i] = v
 		}
@@ -45,11 +57,13 @@ func cloneArray(array []interface{}) []interface{} {
 func cloneMap(m map[interface{}]interface{}) map[interface{}]interface{} {
 	clone := make(map[interface{}]interface{})
 	for k, v := range m {
-		switch cast_element := v.(type) {
+		switch cast_element := thaw(v).(type) {
 		case []interface{}:
-			clone[k] = cloneArray(cast_element)
+			clone[k] = NewLoxArray(cloneArray(cast_element))
 		case map[interface{}]interface{}:
 			clone[k] = cloneMap(cast_element)
+		case *LoxSet:
+			clone[k] = cast_element.Clone()
 		default:
 			clone[k] = v
 		}
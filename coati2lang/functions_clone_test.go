@@ -0,0 +1,21 @@
+package coati2lang
+
+import "testing"
+
+func TestCloneIsDeepAndMutable(t *testing.T) {
+	cases := []struct {
+		source string
+		want   string
+	}{
+		{"var g = clone(freeze({inner: [1, 2]})); var result = typeof(g.inner);", "array"},
+		{"var c = clone({a: freeze([1, {b: 2}])}); c.a[0] = 9; c.a[1].b = 3; var result = sprint(c);", "map[a:[9 map[b:3]]]"},
+		{"var a = [[1]]; var b = clone(a); b[0].push(2); var result = sprint(a, b);", "[[1]] [[1 2]]"},
+		{"var a = [#{1}]; var b = clone(a); b[0].add(2); var result = sprint(a, b);", "[#{1}] [#{1, 2}]"},
+		{"var m = {s: #{1}}; var c = clone(m); c.s.add(2); var result = sprint(m.s);", "#{1}"},
+	}
+	for _, c := range cases {
+		if got := runSandboxed(t, c.source, nil); got != c.want {
+			t.Errorf("%s\ngot  %v\nwant %s", c.source, got, c.want)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package coati2lang
 
 import (
 	"fmt"
+	"strconv"
+	"unicode/utf8"
 )
 
 func init() {
@@ -12,6 +14,8 @@ func init() {
 	GlobalFx["len"] = Len{}
 }
 
+var printlnSignature = sig("(...values: any) -> nil", "Prints the values separated by spaces, then a newline.")
+
 type Println struct {
 }
 
@@ -21,9 +25,15 @@ func (c Println) Call(interpreter *Interpreter, arguments []interface{}, this in
 }
 
 func (c Println) Arity() int {
-	return -1
+	return printlnSignature.Arity()
+}
+
+func (c Println) Signature() *Signature {
+	return printlnSignature
 }
 
+var printSignature = sig("(...values: any) -> number", "Prints the values; returns the number of bytes written.")
+
 type Print struct {
 }
 
@@ -33,9 +43,15 @@ func (c Print) Call(interpreter *Interpreter, arguments []interface{}, this inte
 }
 
 func (c Print) Arity() int {
-	return -1
+	return printSignature.Arity()
+}
+
+func (c Print) Signature() *Signature {
+	return printSignature
 }
 
+var fprintSignature = sig("(...values: any) -> number", "Like println; returns the number of bytes written.")
+
 type Fprint struct {
 }
 
@@ -45,9 +61,15 @@ func (c Fprint) Call(interpreter *Interpreter, arguments []interface{}, this int
 }
 
 func (c Fprint) Arity() int {
-	return -1
+	return fprintSignature.Arity()
+}
+
+func (c Fprint) Signature() *Signature {
+	return fprintSignature
 }
 
+var sprintSignature = sig("(...values: any) -> string", "Formats the values into a string.")
+
 type Sprint struct {
 }
 
@@ -56,16 +78,60 @@ func (c Sprint) Call(interpreter *Interpreter, arguments []interface{}, this int
 }
 
 func (c Sprint) Arity() int {
-	return -1
+	return sprintSignature.Arity()
 }
 
+func (c Sprint) Signature() *Signature {
+	return sprintSignature
+}
+
+var lenSignature = sig("(value: string|array|tuple|map|set) -> number", "Number of characters or elements.")
+
 type Len struct {
 }
 
 func (c Len) Call(interpreter *Interpreter, arguments []interface{}, this interface{}) interface{} {
-	return len(arguments)
+	switch value := thaw(arguments[0]).(type) {
+	case string:
+		return float64(utf8.RuneCountInString(value))
+	case []interface{}:
+		return float64(len(value))
+	case map[interface{}]interface{}:
+		return float64(len(value))
+	case *LoxSet:
+		return float64(value.Len())
+	}
+	raise(Token{}, TYPE_ERROR, "Can't get the length of a value of type '%s'.", typeName(arguments[0]))
+	return nil
 }
 
 func (c Len) Arity() int {
-	return 1
+	return lenSignature.Arity()
+}
+
+func (c Len) Signature() *Signature {
+	return lenSignature
+}
+
+// VisitPrintStmt implementa la sentencia print de Lox (solo en modo estricto).
+func (i *Interpreter) VisitPrintStmt(stmt PrintStmt) interface{} {
+	fmt.Println(i.stringify(i.full_evaluate(stmt.Expression)))
+	return nil
+}
+
+// stringify formatea un valor como lo hace el interprete del libro.
+func (i *Interpreter) stringify(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "nil"
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case Function:
+		return "<fn " + v.Name.Lexeme + ">"
+	case boundMethod, *LoxClass, *LoxInstance:
+		return fmt.Sprint(v)
+	case LoxCallable:
+		return "<native fn>"
+	}
+	return fmt.Sprint(value)
 }
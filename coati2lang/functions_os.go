@@ -0,0 +1,96 @@
+package coati2lang
+
+import (
+	"io"
+	"os"
+)
+
+func init() {
+	GlobalFx["readfile"] = ReadFile{}
+	GlobalFx["writefile"] = WriteFile{}
+	GlobalFx["getenv"] = Getenv{}
+}
+
+var readFileSignature = sig("(path: string) -> string", "Contents of a file. Requires fs:read.")
+
+type ReadFile struct {
+}
+
+func (c ReadFile) Call(interpreter *Interpreter, arguments []interface{}, this interface{}) interface{} {
+	request := interpreter.authorize("readfile", c, arguments)
+	file := interpreter.openAuthorized("readfile", request, os.O_RDONLY)
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		raise(Token{}, IO_ERROR, "readfile: %s.", err)
+	}
+	return string(content)
+}
+
+func (c ReadFile) Arity() int {
+	return readFileSignature.Arity()
+}
+
+func (c ReadFile) Signature() *Signature {
+	return readFileSignature
+}
+
+func (c ReadFile) Requires(arguments []interface{}) Capability {
+	return Capability{Domain: "fs", Action: "read", Resource: realPath(arguments[0].(string))}
+}
+
+var writeFileSignature = sig("(path: string, text: string) -> nil", "Writes text to a file, replacing it. Requires fs:write.")
+
+type WriteFile struct {
+}
+
+func (c WriteFile) Call(interpreter *Interpreter, arguments []interface{}, this interface{}) interface{} {
+	request := interpreter.authorize("writefile", c, arguments)
+	file := interpreter.openAuthorized("writefile", request, os.O_WRONLY|os.O_CREATE)
+	defer file.Close()
+	if err := file.Truncate(0); err != nil {
+		raise(Token{}, IO_ERROR, "writefile: %s.", err)
+	}
+	if _, err := file.WriteString(arguments[1].(string)); err != nil {
+		raise(Token{}, IO_ERROR, "writefile: %s.", err)
+	}
+	return nil
+}
+
+func (c WriteFile) Arity() int {
+	return writeFileSignature.Arity()
+}
+
+func (c WriteFile) Signature() *Signature {
+	return writeFileSignature
+}
+
+func (c WriteFile) Requires(arguments []interface{}) Capability {
+	return Capability{Domain: "fs", Action: "write", Resource: realPath(arguments[0].(string))}
+}
+
+var getenvSignature = sig("(name: string) -> string|nil", "Value of an environment variable, or nil. Requires os:env.")
+
+type Getenv struct {
+}
+
+func (c Getenv) Call(interpreter *Interpreter, arguments []interface{}, this interface{}) interface{} {
+	interpreter.authorize("getenv", c, arguments)
+	value, ok := os.LookupEnv(arguments[0].(string))
+	if !ok {
+		return nil
+	}
+	return value
+}
+
+func (c Getenv) Arity() int {
+	return getenvSignature.Arity()
+}
+
+func (c Getenv) Signature() *Signature {
+	return getenvSignature
+}
+
+func (c Getenv) Requires(arguments []interface{}) Capability {
+	return Capability{Domain: "os", Action: "env", Resource: arguments[0].(string)}
+}
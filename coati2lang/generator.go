@@ -0,0 +1,213 @@
+package coati2lang
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Generator ejecuta el cuerpo de una funcion con yield en su propia goroutine.
+// Solo una de las dos goroutines corre a la vez: el llamador espera en yield
+// y el generador espera en resume.
+type Generator struct {
+	function    Function
+	arguments   []interface{}
+	this        interface{}
+	interpreter *Interpreter
+	// site es donde se pidio el primer valor; es la linea del frame del
+	// generador en la traza.
+	site    Token
+	started bool
+	done    bool
+	closing bool
+	resume  chan bool
+	yield   chan generatorResult
+	exited  chan struct{}
+}
+
+type generatorResult struct {
+	value interface{}
+	done  bool
+	err   interface{}
+}
+
+type generatorClosed struct{}
+
+func NewGenerator(interpreter *Interpreter, function Function, arguments []interface{}, this interface{}) *Generator {
+	return &Generator{
+		function:    function,
+		arguments:   arguments,
+		this:        this,
+		interpreter: interpreter,
+		resume:      make(chan bool),
+		yield:       make(chan generatorResult),
+		exited:      make(chan struct{}),
+	}
+}
+
+func (g *Generator) String() string {
+	return "<generator " + g.function.Name.Lexeme + ">"
+}
+
+func (g *Generator) run() {
+	defer close(g.exited)
+	child := g.interpreter.fork()
+	child.generator = g
+	defer func() {
+		if r := recover(); r != nil {
+			// Al cerrarlo nadie espera el resultado, ni siquiera un error
+			// de un finally.
+			if _, ok := r.(generatorClosed); ok || g.closing {
+				return
+			}
+			g.yield <- generatorResult{err: r, done: true}
+		}
+	}()
+	value := child.callFrame(callableName(g.function), g.site, func() interface{} {
+		return g.function.call(child, g.arguments, g.this)
+	})
+	g.yield <- generatorResult{value: value, done: true}
+}
+
+func (g *Generator) Next(site Token) (interface{}, bool) {
+	if g.done {
+		return nil, true
+	}
+	if !g.started {
+		g.started = true
+		g.site = site
+		g.interpreter.generators[g] = true
+		go g.run()
+	} else {
+		g.resume <- true
+	}
+	result := <-g.yield
+	if result.done {
+		g.done = true
+		delete(g.interpreter.generators, g)
+	}
+	if result.err != nil {
+		panic(result.err)
+	}
+	return result.value, result.done
+}
+
+// Close termina la goroutine de un generador que quedo a medias y espera a
+// que salga, asi no corre en paralelo con el llamador.
+func (g *Generator) Close() {
+	if g.started && !g.done {
+		g.closing = true
+		g.resume <- false
+		<-g.exited
+	}
+	g.done = true
+	delete(g.interpreter.generators, g)
+}
+
+func (g *Generator) Get(name string) (interface{}, bool) {
+	switch name {
+	case "next", "close":
+		return generatorMethod{generator: g, name: name}, true
+	}
+	return nil, false
+}
+
+type generatorMethod struct {
+	generator *Generator
+	name      string
+}
+
+func (m generatorMethod) Call(interpreter *Interpreter, arguments []interface{}, this interface{}) interface{} {
+	if m.name == "close" {
+		m.generator.Close()
+		return nil
+	}
+	value, done := m.generator.Next(Token{})
+	return map[interface{}]interface{}{"value": value, "done": done}
+}
+
+func (m generatorMethod) Arity() int {
+	return 0
+}
+
+func (i *Interpreter) VisitYieldStmt(stmt Yield) interface{} {
+	if i.generator == nil {
+		raise(stmt.Keyword, RUNTIME_ERROR, "Can't yield outside of a generator.")
+	}
+	var value interface{}
+	if stmt.Value != nil {
+		value = i.full_evaluate(stmt.Value)
+	}
+	if i.generator.closing {
+		// Un yield en el finally de un generador que se esta cerrando.
+		panic(generatorClosed{})
+	}
+	i.generator.yield <- generatorResult{value: value}
+	if !<-i.generator.resume {
+		panic(generatorClosed{})
+	}
+	return nil
+}
+
+func (i *Interpreter) VisitForInStmt(stmt ForIn) interface{} {
+	defer i.locate(stmt.Name)
+	iterable := i.full_evaluate(stmt.Iterable)
+	i.iterate(iterable, stmt.Name, func(item interface{}) {
+		enviroment := NewEnviroment(i.enviroment)
+		enviroment.Define(stmt.Name.Lexeme, item)
+		i.executeBlock([]Stmt{stmt.Body}, *enviroment)
+	})
+	return nil
+}
+
+func (i *Interpreter) iterate(iterable interface{}, site Token, fn func(item interface{})) {
+	switch value := thaw(iterable).(type) {
+	case []interface{}:
+		for _, item := range value {
+			fn(item)
+		}
+	case []string:
+		for _, item := range value {
+			fn(item)
+		}
+	case string:
+		for _, char := range value {
+			fn(string(char))
+		}
+	case map[interface{}]interface{}:
+		for _, key := range sortedKeys(value) {
+			fn(key)
+		}
+	case *LoxSet:
+		for _, item := range value.Values() {
+			fn(item)
+		}
+	case *LoxEnum:
+		for _, member := range value.Members {
+			fn(member)
+		}
+	case *Generator:
+		// Si el cuerpo corta el recorrido (throw, return) el generador se
+		// cierra igual.
+		defer value.Close()
+		for {
+			item, done := value.Next(site)
+			if done {
+				return
+			}
+			fn(item)
+		}
+	default:
+		raise(Token{}, TYPE_ERROR, "Can't iterate over a value of type '%s'.", typeName(iterable))
+	}
+}
+
+func sortedKeys(m map[interface{}]interface{}) []interface{} {
+	keys := make([]interface{}, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(a, b int) bool {
+		return fmt.Sprint(keys[a]) < fmt.Sprint(keys[b])
+	})
+	return keys
+}
@@ -0,0 +1,122 @@
+package coati2lang
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func interpretContext(t *testing.T, ctx context.Context, source string) error {
+	t.Helper()
+	tokens, errs := ScanTokens(source)
+	stmts, parseErrs := NewParser(tokens).Parse()
+	if errs = append(errs, parseErrs...); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	interp := NewInterpreter(stmts)
+	if errs := NewResolver(interp).Resolve(stmts); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	_, err := interp.InterpretContext(ctx)
+	return err
+}
+
+// waitGoroutines espera a que vuelva a haber a lo sumo want goroutines.
+func waitGoroutines(t *testing.T, want int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > want && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := runtime.NumGoroutine(); got > want {
+		t.Errorf("%d goroutines left, want at most %d", got, want)
+	}
+}
+
+const naturals = `
+fun naturals() {
+  var n = 0;
+  while (true) {
+    yield n;
+    n = n + 1;
+  }
+}
+`
+
+func TestGeneratorsClosedAfterInterpret(t *testing.T) {
+	before := runtime.NumGoroutine()
+	source := naturals + `
+var i = 0;
+while (i < 100) {
+  var it = naturals();
+  it.next();
+  i = i + 1;
+}
+`
+	if err := interpretContext(t, context.Background(), source); err != nil {
+		t.Fatal(err)
+	}
+	waitGoroutines(t, before)
+}
+
+func TestGeneratorClosedWhenForInThrows(t *testing.T) {
+	before := runtime.NumGoroutine()
+	source := naturals + `
+var i = 0;
+while (i < 50) {
+  try {
+    for (n in naturals()) {
+      if (n == 3) throw "stop";
+    }
+  } catch (e) {}
+  i = i + 1;
+}
+`
+	if err := interpretContext(t, context.Background(), source); err != nil {
+		t.Fatal(err)
+	}
+	waitGoroutines(t, before)
+}
+
+func TestGeneratorsClosedOnCancel(t *testing.T) {
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	source := naturals + `
+var open = [];
+var i = 0;
+while (true) {
+  var it = naturals();
+  it.next();
+  if (i < 20) open.push(it);
+  i = i + 1;
+}
+`
+	if err := interpretContext(t, ctx, source); err == nil {
+		t.Fatal("expected a CancelledError")
+	}
+	waitGoroutines(t, before)
+}
+
+func TestGeneratorFrameInStackTrace(t *testing.T) {
+	source := `
+fun gen() {
+  yield 1;
+  throw "boom";
+}
+fun consume() {
+  for (x in gen()) {}
+}
+consume();
+`
+	err := interpretContext(t, context.Background(), source)
+	runtimeError, ok := err.(*RuntimeError)
+	if !ok {
+		t.Fatalf("got %v, want a RuntimeError", err)
+	}
+	want := "  at gen (line 4)\n  at consume (line 7)\n  at <script> (line 9)\n"
+	if got := runtimeError.StackTrace(); got != want {
+		t.Errorf("stack trace\n%s\nwant\n%s", got, want)
+	}
+}
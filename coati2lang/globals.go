@@ -3,6 +3,8 @@ package coati2lang
 var (
 	ERROR_FILE_NOT_FOUND = 41
 	ERROR_SYNTAX         = 42
-	PrintFlag            = true
-	HasError             = false
+	ERROR_RESOLVE        = 43
+	ERROR_RUNTIME        = 44
+	ERROR_LINT           = 45
+	MAX_CALL_DEPTH       = 10000
 )
@@ -1,9 +1,9 @@
 package coati2lang
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"log"
 	"math"
 	"time"
 )
@@ -21,20 +21,55 @@ type LoxCallable interface {
 	Arity() int
 }
 
+type LoxObject interface {
+	Get(name string) (interface{}, bool)
+}
+
 type Interpreter struct {
 	Stmts      []Stmt
 	enviroment *Enviroment
+	globals    *Enviroment
+	locals     map[Token]int
+	resolved   bool
+	generator  *Generator
+	// generators son los generadores arrancados que no terminaron; se
+	// cierran al salir de InterpretContext para no dejar goroutines.
+	generators map[*Generator]bool
+	extensions map[string]map[string]LoxCallable
+	frames     []Frame
+
+	// Strict activa la semantica de Lox del libro (print, formato de valores).
+	Strict bool
+	// MaxCallDepth es la cantidad maxima de llamadas anidadas antes de
+	// levantar un StackOverflowError; 0 o menos es sin limite.
+	MaxCallDepth int
+	// MaxSteps limita los nodos evaluados y MaxAllocation los bytes
+	// (aproximados) de strings, arrays, mapas y sets creados; 0 es sin limite.
+	MaxSteps      int64
+	MaxAllocation int64
+	budget        *budget
+	// Capabilities son los permisos de las funciones nativas privilegiadas
+	// (archivos, entorno); nil las niega a todas. AuditHook, si esta,
+	// recibe cada uso de esas funciones, permitido o no.
+	Capabilities Capabilities
+	AuditHook    func(AuditEvent)
 }
 
+var clockSignature = sig("() -> number", "Seconds since the Unix epoch.")
+
 type Clock struct {
 }
 
 func (c Clock) Call(interpreter *Interpreter, arguments []interface{}, this interface{}) interface{} {
-	return time.Now().Unix()
+	return float64(time.Now().Unix())
 }
 
 func (c Clock) Arity() int {
-	return 0
+	return clockSignature.Arity()
+}
+
+func (c Clock) Signature() *Signature {
+	return clockSignature
 }
 
 func NewInterpreter(stmts []Stmt) *Interpreter {
@@ -47,16 +82,62 @@ func NewInterpreter(stmts []Stmt) *Interpreter {
 	return &Interpreter{
 		Stmts:      stmts,
 		enviroment: global,
+		globals:    global,
+		locals:     make(map[Token]int),
+		generators: make(map[*Generator]bool),
+		extensions: make(map[string]map[string]LoxCallable),
+
+		MaxCallDepth: MAX_CALL_DEPTH,
 	}
 
 }
 
-func (i *Interpreter) Interpret() interface{} {
-	result := i.executeBlock(i.Stmts, *i.enviroment)
-	return result
+func (i *Interpreter) fork() *Interpreter {
+	return &Interpreter{
+		enviroment: i.enviroment,
+		globals:    i.globals,
+		locals:     i.locals,
+		resolved:   i.resolved,
+		generators: i.generators,
+		extensions: i.extensions,
+		frames:     append([]Frame{}, i.frames...),
+		Strict:     i.Strict,
+
+		MaxCallDepth: i.MaxCallDepth,
+		budget:       i.budget,
+		Capabilities: i.Capabilities,
+		AuditHook:    i.AuditHook,
+	}
+}
+
+func (i *Interpreter) Resolve(name Token, depth int) {
+	i.locals[name] = depth
+}
+
+// Interpret ejecuta el programa. Los errores de ejecucion se devuelven
+// como *RuntimeError en lugar de terminar el proceso.
+func (i *Interpreter) Interpret() (interface{}, error) {
+	return i.InterpretContext(context.Background())
+}
+
+// InterpretContext es Interpret con cancelacion: si ctx se cancela o vence
+// la ejecucion se corta con un CancelledError.
+func (i *Interpreter) InterpretContext(ctx context.Context) (result interface{}, err error) {
+	i.budget = &budget{ctx: ctx, maxSteps: i.MaxSteps, maxAllocation: i.MaxAllocation}
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, asRuntimeError(r)
+		}
+		for generator := range i.generators {
+			generator.Close()
+		}
+	}()
+	result = i.executeBlock(i.Stmts, *i.enviroment)
+	return result, nil
 }
 
 func (i *Interpreter) execute(stmt Stmt) interface{} {
+	i.step()
 	return stmt.AcceptStmt(i)
 }
 
@@ -71,51 +152,77 @@ func (i *Interpreter) VisitReturnStmt(stmt Return) interface{} {
 
 func (i *Interpreter) VisitFunctionStmt(stmt Function) interface{} {
 	function := Function{
-		Name:       stmt.Name,
-		Parameters: stmt.Parameters,
-		Body:       stmt.Body,
-		Closure:    i.enviroment,
+		Name:        stmt.Name,
+		Parameters:  stmt.Parameters,
+		Body:        stmt.Body,
+		Closure:     i.enviroment,
+		IsGenerator: stmt.IsGenerator,
 	}
 	i.enviroment.Define(stmt.Name.Lexeme, function)
 	return nil
 }
 
 func (i *Interpreter) VisitCallExpr(expr Call) interface{} {
-	callee := i.evaluate(expr.Callee)
-
-	if variable, ok := expr.Callee.(Var); ok {
-		value := i.evaluate(variable)
-		if str, ok := value.(string); ok {
-			fx := variable.Selectors[len(variable.Selectors)-1][0].(Literal).Value.(string)
+	defer i.locate(expr.Paren)
 
-			return STRING_FX_MAP[fx](str, i, expr.Arguments)
+	var callee, this interface{}
+	switch target := expr.Callee.(type) {
+	case Get:
+		this = i.evaluate(target.Object)
+		if this == nil && target.Optional {
+			return nil
+		}
+		if result, ok := i.callMethod(expr, target, this); ok {
+			return result
+		}
+		callee = i.selectValue(this, []interface{}{target.Name.Lexeme}, target.Optional, target.Name)
+	case Index:
+		this = i.evaluate(target.Object)
+		if this == nil && target.Optional {
+			return nil
 		}
+		keys := make([]interface{}, len(target.Indexes))
+		for index, indexExpr := range target.Indexes {
+			keys[index] = i.full_evaluate(indexExpr)
+		}
+		callee = i.selectValue(this, keys, target.Optional, target.Bracket)
+	default:
+		callee = i.evaluate(expr.Callee)
+	}
+	if callee == nil && expr.Optional {
+		return nil
 	}
 
 	var arguments []interface{}
 	for _, argument := range expr.Arguments {
-		arguments = append(arguments, i.evaluate(argument))
+		arguments = append(arguments, i.full_evaluate(argument))
+	}
+	if method, ok := i.operatorMethod(callee, "__call"); ok {
+		return i.callFrame(frameName(expr, callee), expr.Paren, func() interface{} {
+			return method.Call(i, arguments, callee)
+		})
 	}
+
 	callable, ok := callee.(LoxCallable)
 	if !ok {
-		fmt.Println("Can only call functions and classes.")
-		return nil
+		raise(expr.Paren, TYPE_ERROR, "Can only call functions and classes.")
 	}
-	if callable.Arity() != -1 && callable.Arity() != len(arguments) {
-		fmt.Println("Expected", callable.Arity(), "arguments but got", len(arguments), ".")
-		return nil
+	name := frameName(expr, callable)
+	if native, ok := callable.(NativeFunction); ok {
+		native.Signature().check(expr.Paren, name, arguments)
+	} else if callable.Arity() != -1 && callable.Arity() != len(arguments) {
+		raise(expr.Paren, ARGUMENT_ERROR, "Expected %d arguments but got %d.", callable.Arity(), len(arguments))
 	}
 
-	//if _, ok := expr.(Var); ok {
-	if expr.This.Name.Lexeme != "" {
-		this := i.full_evaluate(expr.This)
+	result := i.callFrame(name, expr.Paren, func() interface{} {
 		return callable.Call(i, arguments, this)
+	})
+	if _, ok := callable.(NativeFunction); ok {
+		i.charge(expr.Paren, result)
 	}
-	//}
-
-	return callable.Call(i, arguments, nil)
-
+	return result
 }
+
 func (i *Interpreter) VisitWhileStmt(stmt While) interface{} {
 	for i.isTruthy(i.evaluate(stmt.Condition)) {
 		i.execute(stmt.Body)
@@ -174,63 +281,66 @@ func (i *Interpreter) VisitBinaryExpr(expr Binary) interface{} {
 	left := i.evaluate(expr.Left)
 	right := i.evaluate(expr.Right)
 
+	if result, ok := i.overloadBinary(expr.Operator, left, right); ok {
+		return result
+	}
+
+	if i.Strict {
+		i.checkOperands(expr.Operator, left, right)
+	}
+
 	switch expr.Operator.Type {
 	case MINUS:
-		return left.(float64) - right.(float64)
+		a, b := i.numberOperands(expr.Operator, left, right)
+		return a - b
 	case PERCENT:
-		return (left.(float64) * right.(float64) / 100.0)
+		a, b := i.numberOperands(expr.Operator, left, right)
+		return a * b / 100.0
 	case PLUS:
 		{
-			if _, ok := left.(float64); ok {
-				return left.(float64) + right.(float64)
+			if isNumber(left) && isNumber(right) {
+				return toFloat(left) + toFloat(right)
 			}
-			if _, ok := left.(string); ok {
-				return left.(string) + right.(string)
+			a, leftIsString := left.(string)
+			b, rightIsString := right.(string)
+			if leftIsString && rightIsString {
+				i.allocate(expr.Operator, int64(len(a)+len(b)))
+				return a + b
 			}
+			raise(expr.Operator, TYPE_ERROR, "Operands must be two numbers or two strings.")
 			return nil
 		}
 	case SLASH:
-		return left.(float64) / right.(float64)
+		a, b := i.numberOperands(expr.Operator, left, right)
+		return a / b
 	case STAR_STAR:
-		return math.Pow(left.(float64), right.(float64))
+		a, b := i.numberOperands(expr.Operator, left, right)
+		return math.Pow(a, b)
 	case STAR:
 		// validate rigth is string
 		{
 			_, right_is_string := right.(string)
 			_, left_is_string := left.(string)
-			_, right_is_number := right.(float64)
-			_, left_is_number := left.(float64)
+			right_is_number := isNumber(right)
+			left_is_number := isNumber(left)
 
 			if right_is_string && left_is_number {
-				var result string
-				for i := 0; i < int(left.(float64)); i++ {
-					result += right.(string)
-				}
-				return result
+				return i.repeat(expr.Operator, right.(string), toFloat(left))
 			}
 
 			if right_is_number && left_is_string {
-				var result string
-				for i := 0; i < int(right.(float64)); i++ {
-					result += left.(string)
-				}
-				return result
+				return i.repeat(expr.Operator, left.(string), toFloat(right))
 			}
 
 			if right_is_number && left_is_number {
-				return left.(float64) * right.(float64)
+				return toFloat(left) * toFloat(right)
 			}
 
+			raise(expr.Operator, TYPE_ERROR, "Operands must be numbers, or a string and a number.")
 			return nil
 		}
-	case GREATER:
-		return left.(float64) > right.(float64)
-	case GREATER_EQUAL:
-		return left.(float64) >= right.(float64)
-	case LESS:
-		return left.(float64) < right.(float64)
-	case LESS_EQUAL:
-		return left.(float64) <= right.(float64)
+	case GREATER, GREATER_EQUAL, LESS, LESS_EQUAL:
+		return i.compareValues(expr.Operator, left, right)
 	case BANG_EQUAL:
 		return !i.isEqual(left, right)
 	case EQUAL_EQUAL:
@@ -240,12 +350,41 @@ func (i *Interpreter) VisitBinaryExpr(expr Binary) interface{} {
 	}
 }
 
+func (i *Interpreter) numberOperands(operator Token, left interface{}, right interface{}) (float64, float64) {
+	if !isNumber(left) || !isNumber(right) {
+		raise(operator, TYPE_ERROR, "Operands must be numbers.")
+	}
+	return toFloat(left), toFloat(right)
+}
+
+func (i *Interpreter) numberOperand(operator Token, value interface{}) float64 {
+	if !isNumber(value) {
+		raise(operator, TYPE_ERROR, "Operand must be a number.")
+	}
+	return toFloat(value)
+}
+
+// checkOperands reproduce los errores de tipo de Lox en modo estricto.
+func (i *Interpreter) checkOperands(operator Token, left interface{}, right interface{}) {
+	_, leftIsNumber := left.(float64)
+	_, rightIsNumber := right.(float64)
+	switch operator.Type {
+	case PLUS:
+		_, leftIsString := left.(string)
+		_, rightIsString := right.(string)
+		if !(leftIsNumber && rightIsNumber) && !(leftIsString && rightIsString) {
+			raise(operator, TYPE_ERROR, "Operands must be two numbers or two strings.")
+		}
+	case MINUS, STAR, SLASH, GREATER, GREATER_EQUAL, LESS, LESS_EQUAL:
+		if !leftIsNumber || !rightIsNumber {
+			raise(operator, TYPE_ERROR, "Operands must be numbers.")
+		}
+	}
+}
+
 func (i *Interpreter) VisitGroupingABSExpr(expr GroupingABS) interface{} {
 	value := i.evaluate(expr.Expression)
-	if value.(float64) < 0 {
-		return -value.(float64)
-	}
-	return value
+	return math.Abs(i.numberOperand(expr.Pipe, value))
 }
 
 func (i *Interpreter) VisitGroupingExpr(expr Grouping) interface{} {
@@ -253,21 +392,42 @@ func (i *Interpreter) VisitGroupingExpr(expr Grouping) interface{} {
 }
 
 func (i *Interpreter) VisitLiteralExpr(expr Literal) interface{} {
+	switch value := expr.Value.(type) {
+	case []Expr:
+		var values []interface{} = make([]interface{}, len(value))
+		for index, item := range value {
+			values[index] = i.full_evaluate(item)
+		}
+		return i.charge(Token{}, NewLoxArray(values))
+	case []ItemVar:
+		var values map[interface{}]interface{} = make(map[interface{}]interface{})
+		for _, item := range value {
+			key := i.full_evaluate(item.Key)
+			checkKey(Token{}, key)
+			values[key] = i.full_evaluate(item.Value)
+		}
+		return i.charge(Token{}, values)
+	}
 	return expr.Value
 }
 
 func (i *Interpreter) VisitUnaryExpr(expr Unary) interface{} {
 	value := i.evaluate(expr.Value)
 
+	if result, ok := i.overloadUnary(expr.Operator, value); ok {
+		return result
+	}
 	switch expr.Operator.Type {
 	case MINUS:
-		return -(value.(float64))
+		return -i.numberOperand(expr.Operator, value)
 	case PLUS_PLUS:
-		return value.(float64) + 1
+		return i.numberOperand(expr.Operator, value) + 1
 	case MINUS_MINUS:
-		return value.(float64) - 1
+		return i.numberOperand(expr.Operator, value) - 1
 	case BANG:
 		return !(i.isTruthy(value))
+	case TYPEOF:
+		return typeName(value)
 	default:
 		return nil
 	}
@@ -284,17 +444,18 @@ func (i *Interpreter) VisitVar(stmt Var) interface{} {
 		for index, value := range stmt.InitializerArray {
 			values[index] = i.full_evaluate(value)
 		}
-		value = values
+		value = i.charge(stmt.Name, NewLoxArray(values))
 	}
 
 	if stmt.InitializerMap != nil {
 		var values map[interface{}]interface{} = make(map[interface{}]interface{})
 		for _, item := range stmt.InitializerMap {
 			key := i.full_evaluate(item.Key)
+			checkKey(stmt.Name, key)
 			value := i.full_evaluate(item.Value)
 			values[key] = value
 		}
-		value = values
+		value = i.charge(stmt.Name, values)
 	}
 
 	if stmt.InitializerFx != nil {
@@ -306,92 +467,324 @@ func (i *Interpreter) VisitVar(stmt Var) interface{} {
 	return nil
 }
 
+func (i *Interpreter) lookUpVariable(name Token) (interface{}, bool) {
+	if distance, ok := i.locals[name]; ok {
+		return i.enviroment.GetAt(distance, name.Lexeme)
+	}
+	if i.resolved {
+		return i.globals.Get(name.Lexeme)
+	}
+	return i.enviroment.Get(name.Lexeme)
+}
+
+func (i *Interpreter) assignVariable(name Token, value interface{}) {
+	if distance, ok := i.locals[name]; ok {
+		i.enviroment.AssignAt(distance, name.Lexeme, value)
+		return
+	}
+	if i.resolved {
+		i.globals.Assign(name.Lexeme, value)
+		return
+	}
+	i.enviroment.Assign(name.Lexeme, value)
+}
+
 func (i *Interpreter) VisitVariableExpr(expr Var) interface{} {
-	value, ok := i.enviroment.Get(expr.Name.Lexeme)
+	value, ok := i.lookUpVariable(expr.Name)
 
 	if !ok {
 		if expr.Sub {
 			expr.Sub = false
 			i.VisitVar(expr)
-			value, ok = i.enviroment.Get(expr.Name.Lexeme)
+			value, ok = i.lookUpVariable(expr.Name)
 		}
 		if !ok {
-			log.Fatalln("Undefined variable '" + expr.Name.Lexeme + "'.")
+			raiseHint(expr.Name, NAME_ERROR, suggest(expr.Name.Lexeme, i.enviroment.Names()), "Undefined variable '%s'.", expr.Name.Lexeme)
 		}
 	}
-	if len(expr.Selectors) > 0 {
-		for _, arraySelector := range expr.Selectors {
-			if array, ok := value.([]interface{}); ok {
-				values := make([]interface{}, len(arraySelector))
-				for index, selExpr := range arraySelector {
-					selector := i.evaluate(selExpr)
-					pos := int(selector.(float64))
-					if pos < 0 {
-						pos = len(array) + pos
-					}
-					values[index] = array[pos]
-				}
-				if len(values) == 1 {
-					value = values[0]
+
+	return value
+}
+
+func (i *Interpreter) VisitGetExpr(expr Get) interface{} {
+	object := i.evaluate(expr.Object)
+	if _, ok := object.(*LoxInstance); i.Strict && !ok {
+		raise(expr.Name, TYPE_ERROR, "Only instances have properties.")
+	}
+	return i.selectValue(object, []interface{}{expr.Name.Lexeme}, expr.Optional, expr.Name)
+}
+
+func (i *Interpreter) VisitIndexExpr(expr Index) interface{} {
+	object := i.evaluate(expr.Object)
+	if object == nil && expr.Optional {
+		return nil
+	}
+	keys := make([]interface{}, len(expr.Indexes))
+	for index, indexExpr := range expr.Indexes {
+		keys[index] = i.full_evaluate(indexExpr)
+	}
+	return i.selectValue(object, keys, expr.Optional, expr.Bracket)
+}
+
+// selectValue resuelve obj.clave y obj[k1, k2, ...]. Con varias claves
+// devuelve un array (o un mapa) con los elementos seleccionados.
+func (i *Interpreter) selectValue(object interface{}, keys []interface{}, optional bool, token Token) interface{} {
+	if object == nil {
+		if optional {
+			return nil
+		}
+		raise(token, TYPE_ERROR, "Can't read '%v' of nil.", keys[0])
+	}
+
+	switch value := thaw(object).(type) {
+	case LoxObject:
+		name := fmt.Sprint(keys[0])
+		property, ok := value.Get(name)
+		if !ok {
+			raiseHint(token, NAME_ERROR, suggest(name, propertyNames(value)), "Undefined property '%s'.", name)
+		}
+		return property
+
+	case string:
+		// Los strings se indexan por runa, no por byte.
+		runes := []rune(value)
+		chars := make([]interface{}, len(keys))
+		for index, key := range keys {
+			number, ok := key.(float64)
+			if !ok {
+				raise(token, TYPE_ERROR, "String index must be a number, got '%v'.", key)
+			}
+			pos := int(number)
+			if pos < 0 {
+				pos = len(runes) + pos
+			}
+			if pos < 0 || pos >= len(runes) {
+				if optional {
 					continue
 				}
-				value = values
-
+				raise(token, INDEX_ERROR, "String index %v out of range.", number)
 			}
+			chars[index] = string(runes[pos])
+		}
+		if len(chars) == 1 {
+			return chars[0]
+		}
+		return NewLoxArray(chars)
 
-			if m, ok := value.(map[interface{}]interface{}); ok {
-				values := make(map[interface{}]interface{})
-				var selector interface{}
-				for _, selExpr := range arraySelector {
-					selector = i.evaluate(selExpr)
-					values[selector] = m[selector]
-				}
-				if len(values) == 1 {
-					value = values[selector]
+	case []interface{}:
+		values := make([]interface{}, len(keys))
+		for index, key := range keys {
+			number, ok := key.(float64)
+			if !ok {
+				raise(token, TYPE_ERROR, "Array index must be a number, got '%v'.", key)
+			}
+			pos := int(number)
+			if pos < 0 {
+				pos = len(value) + pos
+			}
+			if pos < 0 || pos >= len(value) {
+				if optional {
 					continue
 				}
-				value = values
+				raise(token, INDEX_ERROR, "Array index %v out of range.", number)
 			}
+			values[index] = value[pos]
+		}
+		if len(values) == 1 {
+			return values[0]
 		}
+		return NewLoxArray(values)
 
-		if len(expr.Selectors) == 1 {
-			if array, ok := value.([]interface{}); ok {
-				if len(array) == 1 {
-					value = array[0]
-				}
+	case map[interface{}]interface{}:
+		values := make(map[interface{}]interface{})
+		for _, key := range keys {
+			checkKey(token, key)
+			item, found := value[key]
+			// __index solo se consulta para claves que el objeto no tiene.
+			if method, ok := i.operatorMethod(value, "__index"); ok && !found {
+				item = i.callOperator(method, "__index", value, key)
+			}
+			if len(keys) == 1 {
+				return item
 			}
+			values[key] = item
+		}
+		return values
+	}
+
+	raise(token, TYPE_ERROR, "Can't read '%v' of type '%s'.", keys[0], typeName(object))
+	return nil
+}
+
+func (i *Interpreter) VisitSetExpr(expr Set) interface{} {
+	value := i.full_evaluate(expr.Value)
+	object := i.evaluate(expr.Object)
+	if _, err := i.setByPath(expr.Equals, object, i.evaluatePath(expr.Selectors), value); err != nil {
+		raisePath(expr.Equals, err)
+	}
+	return value
+}
+
+func (i *Interpreter) evaluatePath(selectors [][]Expr) []interface{} {
+	path := make([]interface{}, len(selectors))
+	for index, arraySelector := range selectors {
+		for _, selExpr := range arraySelector {
+			path[index] = i.full_evaluate(selExpr)
 		}
 	}
+	return path
+}
+
+// assignTo escribe value en una expresion asignable (variable, propiedad o
+// indice) con la misma semantica que una asignacion del script.
+func (i *Interpreter) assignTo(target Expr, value interface{}) bool {
+	root, selectors, ok := flattenTarget(target)
+	if !ok {
+		return false
+	}
+	if variable, ok := root.(Var); ok {
+		i.VisitAssignExpr(Assign{Name: variable.Name, Selectors: selectors, Value: Literal{Value: value}})
+		return true
+	}
+	if len(selectors) == 0 {
+		return false
+	}
+	i.VisitSetExpr(Set{Object: root, Selectors: selectors, Value: Literal{Value: value}})
+	return true
+}
 
+func (i *Interpreter) VisitCompoundAssignExpr(expr CompoundAssign) interface{} {
+	_, value := i.updateTarget(expr.Target, expr.Operator, func(old interface{}) interface{} {
+		right := i.full_evaluate(expr.Value)
+		return i.evaluate(Binary{Left: Literal{Value: old}, Operator: expr.Operator, Right: Literal{Value: right}})
+	})
 	return value
 }
 
-func (i *Interpreter) setByPath(target interface{}, path []interface{}, value interface{}) (interface{}, error) {
+func (i *Interpreter) VisitIncrementExpr(expr Increment) interface{} {
+	operator := Token{Type: PLUS, Lexeme: "+", Line: expr.Operator.Line, Column: expr.Operator.Column}
+	if expr.Operator.Type == MINUS_MINUS {
+		operator.Type, operator.Lexeme = MINUS, "-"
+	}
+	old, value := i.updateTarget(expr.Target, expr.Operator, func(old interface{}) interface{} {
+		return i.evaluate(Binary{Left: Literal{Value: old}, Operator: operator, Right: Literal{Value: 1.0}})
+	})
+	if expr.Prefix {
+		return value
+	}
+	return old
+}
+
+// updateTarget lee el valor actual de una expresion asignable, calcula el
+// nuevo con update y lo escribe. La raiz y los indices se evaluan una sola vez.
+func (i *Interpreter) updateTarget(target Expr, token Token, update func(old interface{}) interface{}) (interface{}, interface{}) {
+	root, selectors, _ := flattenTarget(target)
+	variable, isVar := root.(Var)
+
+	var object interface{}
+	if isVar {
+		object = i.VisitVariableExpr(variable)
+	} else {
+		object = i.evaluate(root)
+	}
+	path := i.evaluatePath(selectors)
+
+	old := object
+	for _, key := range path {
+		old = i.selectValue(old, []interface{}{key}, false, token)
+	}
+	value := update(old)
+
+	if len(path) == 0 {
+		i.assignVariable(variable.Name, value)
+		return old, value
+	}
+	updated, err := i.setByPath(token, object, path, value)
+	if err != nil {
+		raisePath(token, err)
+	}
+	if isVar && variable.Name.Lexeme != "this" {
+		i.assignVariable(variable.Name, updated)
+	}
+	return old, value
+}
+
+// pathError es un error de setByPath que no es un TypeError.
+type pathError struct {
+	kind    ErrorKind
+	message string
+}
+
+func (e pathError) Error() string {
+	return e.message
+}
+
+// raisePath levanta un error de setByPath; por defecto es un TypeError.
+func raisePath(token Token, err error) {
+	kind := TYPE_ERROR
+	if e, ok := err.(pathError); ok {
+		kind = e.kind
+	}
+	raise(token, kind, "%s", err)
+}
+
+func (i *Interpreter) setByPath(token Token, target interface{}, path []interface{}, value interface{}) (interface{}, error) {
 	// Si no hay más elementos en la path, simplemente asigna el valor
 	if len(path) == 0 {
 		return nil, errors.New("path is too short")
 	}
 
 	switch t := target.(type) {
-	case []interface{}:
-		// Trata target como un slice
-		index := int(path[0].(float64))
+	case Tuple:
+		return nil, errTupleAssign
+
+	case FrozenMap:
+		return nil, errFrozenAssign
+
+	case *LoxArray:
+		// Trata target como un array; se modifica en el lugar
+		number, ok := path[0].(float64)
+		if !ok {
+			return nil, fmt.Errorf("Array index must be a number, got '%v'.", path[0])
+		}
+		number = math.Trunc(number)
+		if number < 0 {
+			number += float64(len(t.Elements))
+		}
+		if number < 0 {
+			return nil, pathError{kind: INDEX_ERROR, message: fmt.Sprintf("Array index %v out of range.", path[0])}
+		}
 
-		// Si el índice está fuera de rango, extiende el slice
-		for len(t) <= index {
-			t = append(t, nil)
+		// Si el índice está fuera de rango, extiende el slice de una vez,
+		// cobrando los elementos nuevos antes de reservarlos.
+		if grow := number - float64(len(t.Elements)) + 1; grow >= 1 {
+			n, size := repeatSize(ELEMENT_SIZE, grow)
+			i.allocate(token, size)
+			if size == math.MaxInt64 {
+				return nil, pathError{kind: INDEX_ERROR, message: fmt.Sprintf("Array index %v out of range.", path[0])}
+			}
+			extended := make([]interface{}, len(t.Elements)+n)
+			copy(extended, t.Elements)
+			t.Elements = extended
 		}
+		index := int(number)
 
 		// Si esta es la última parte de la path, asigna el valor
 		if len(path) == 1 {
-			t[index] = value
+			t.Elements[index] = value
 			return t, nil
 		}
-		return i.setByPath(t[index], path[1:], value)
+		new, err := i.setByPath(token, t.Elements[index], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		t.Elements[index] = new
+		return t, nil
 
 	case map[interface{}]interface{}:
 		// Trata target como un mapa
 		key := path[0]
+		checkKey(token, key)
 		if len(path) == 1 {
 			t[key] = value
 			return t, nil
@@ -400,47 +793,58 @@ func (i *Interpreter) setByPath(target interface{}, path []interface{}, value in
 			return nil, errors.New("key not found")
 		}
 
-		if _, ok := t[key].([]interface{}); ok {
-			new, err := i.setByPath(t[key], path[1:], value)
-			if err != nil {
-				return nil, err
-			}
-			t[key] = new
-			return t, nil
+		new, err := i.setByPath(token, t[key], path[1:], value)
+		if err != nil {
+			return nil, err
 		}
+		t[key] = new
+		return t, nil
 
-		return i.setByPath(t[key], path[1:], value)
+	case *LoxInstance:
+		name := fmt.Sprint(path[0])
+		if len(path) == 1 {
+			t.Set(name, value)
+			return t, nil
+		}
+		field, ok := t.Fields[name]
+		if !ok {
+			return nil, fmt.Errorf("undefined property '%s'", name)
+		}
+		new, err := i.setByPath(token, field, path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		t.Set(name, new)
+		return t, nil
 
 	default:
-		return nil, errors.New("unsupported type")
+		if i.Strict {
+			return nil, errors.New("Only instances have fields.")
+		}
+		return nil, fmt.Errorf("Can't set '%v' on a value of type '%s'.", path[0], typeName(target))
 	}
 }
 
 func (i *Interpreter) VisitAssignExpr(expr Assign) interface{} {
 	value := i.full_evaluate(expr.Value)
-	old, ok := i.enviroment.Get(expr.Name.Lexeme)
+	old, ok := i.lookUpVariable(expr.Name)
 	if !ok {
-		log.Fatalln("Undefined variable '" + expr.Name.Lexeme + "'.")
+		raiseHint(expr.Name, NAME_ERROR, suggest(expr.Name.Lexeme, i.enviroment.Names()), "Undefined variable '%s'.", expr.Name.Lexeme)
 	}
 
-	path_var := make([]interface{}, len(expr.Selectors))
 	if len(expr.Selectors) > 0 {
-		for index, arraySelector := range expr.Selectors {
-			for _, selExpr := range arraySelector {
-				path_var[index] = i.full_evaluate(selExpr)
-			}
-		}
-		new, err := i.setByPath(old, path_var, value)
+		path_var := i.evaluatePath(expr.Selectors)
+		new, err := i.setByPath(expr.Name, old, path_var, value)
 		if err != nil {
-			log.Fatalln(err)
+			raisePath(expr.Name, err)
 		}
 		if expr.Name.Lexeme != "this" {
 			//TODO: verificar que funcione en todos los casos de uso
-			i.enviroment.Assign(expr.Name.Lexeme, new)
+			i.assignVariable(expr.Name, new)
 		}
 		return value
 	} else {
-		i.enviroment.Assign(expr.Name.Lexeme, value)
+		i.assignVariable(expr.Name, value)
 	}
 	return value
 }
@@ -451,28 +855,11 @@ func (i *Interpreter) full_evaluate(expr Expr) interface{} {
 		value := i.full_evaluate(expr_v)
 		return value
 	}
-	expr_a, ok := value.([]Expr)
-	if ok {
-		var values []interface{} = make([]interface{}, len(expr_a))
-		for index, value := range expr_a {
-			values[index] = i.full_evaluate(value)
-		}
-		return values
-	}
-
-	expr_m, ok := value.([]ItemVar)
-	if ok {
-		var values map[interface{}]interface{} = make(map[interface{}]interface{})
-		for _, value := range expr_m {
-			values[i.full_evaluate(value.Key)] = i.full_evaluate(value.Value)
-		}
-		return values
-	}
-
 	return value
 }
 
 func (i *Interpreter) evaluate(expr Expr) interface{} {
+	i.step()
 	return expr.AcceptExpr(i)
 }
 
@@ -485,13 +872,3 @@ func (i *Interpreter) isTruthy(object interface{}) bool {
 	}
 	return true
 }
-
-func (i *Interpreter) isEqual(a interface{}, b interface{}) bool {
-	if a == nil && b == nil {
-		return true
-	}
-	if a == nil {
-		return false
-	}
-	return a == b
-}
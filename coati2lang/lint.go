@@ -0,0 +1,836 @@
+package coati2lang
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Reglas del linter; son los codigos de los Diagnostic que devuelve Lint y
+// los nombres que se usan en las directivas // lint:disable.
+const (
+	LINT_UNUSED_VARIABLE       = "unused-variable"
+	LINT_UNUSED_PARAMETER      = "unused-parameter"
+	LINT_SHADOW                = "shadow"
+	LINT_UNREACHABLE           = "unreachable"
+	LINT_UNDECLARED_ASSIGNMENT = "undeclared-assignment"
+	LINT_SELF_COMPARISON       = "self-comparison"
+	LINT_CONSTANT_CONDITION    = "constant-condition"
+	LINT_DIRECTIVE             = "lint-directive"
+)
+
+var LintRules = []string{
+	LINT_UNUSED_VARIABLE,
+	LINT_UNUSED_PARAMETER,
+	LINT_SHADOW,
+	LINT_UNREACHABLE,
+	LINT_UNDECLARED_ASSIGNMENT,
+	LINT_SELF_COMPARISON,
+	LINT_CONSTANT_CONDITION,
+}
+
+// Lint analiza source sin ejecutarlo. Si no parsea devuelve los errores de
+// sintaxis; si no, las advertencias de las reglas habilitadas.
+func Lint(file, source string, strict bool) ([]Diagnostic, []error) {
+	scanner := NewScanner(source)
+	scanner.Strict = strict
+	tokens := scanner.ScanTokens()
+	parser := NewParser(tokens)
+	parser.Strict = strict
+	stmts, errs := parser.Parse()
+	if errs = append(scanner.Errors, errs...); len(errs) > 0 {
+		return nil, errs
+	}
+	return NewLinter(file, scanner.Comments).Lint(stmts), nil
+}
+
+type lintBinding struct {
+	name Token
+	kind string
+	used bool
+}
+
+// lintDirective es un comentario // lint:<action> regla, regla. Sin reglas
+// aplica a todas.
+type lintDirective struct {
+	line   int
+	action string
+	rules  []string
+}
+
+func (d lintDirective) covers(rule string) bool {
+	if len(d.rules) == 0 {
+		return true
+	}
+	for _, r := range d.rules {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// Linter recorre el AST como el Resolver, pero en lugar de errores junta
+// advertencias sobre codigo que probablemente este mal.
+type Linter struct {
+	file        string
+	scopes      []map[string]*lintBinding
+	globals     map[string]*lintBinding
+	directives  []lintDirective
+	Diagnostics []Diagnostic
+}
+
+func NewLinter(file string, comments []Comment) *Linter {
+	l := &Linter{
+		file:    file,
+		scopes:  []map[string]*lintBinding{},
+		globals: make(map[string]*lintBinding),
+	}
+	for _, comment := range comments {
+		l.parseDirective(comment)
+	}
+	return l
+}
+
+func (l *Linter) parseDirective(comment Comment) {
+	text := strings.TrimSpace(comment.Text)
+	if !strings.HasPrefix(text, "lint:") {
+		return
+	}
+	// Los errores de la directiva apuntan a "lint:", despues de "//".
+	indent := strings.Index(comment.Text, "lint:")
+	at := Token{Lexeme: "lint:", Line: comment.Line, Column: comment.Column + 2 + utf8.RuneCountInString(comment.Text[:indent])}
+	// Lo que sigue a "--" es la explicacion de la directiva.
+	text, _, _ = strings.Cut(strings.TrimPrefix(text, "lint:"), "--")
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+	if len(fields) == 0 {
+		l.report(LINT_DIRECTIVE, at, "Expect an action after 'lint:'.")
+		return
+	}
+
+	directive := lintDirective{line: comment.Line, action: fields[0], rules: fields[1:]}
+	switch directive.action {
+	case "disable", "enable", "disable-line", "disable-next-line":
+	default:
+		l.report(LINT_DIRECTIVE, at, fmt.Sprintf("Unknown lint action '%s'.", directive.action),
+			suggest(directive.action, []string{"disable", "enable", "disable-line", "disable-next-line"}))
+		return
+	}
+	for _, rule := range directive.rules {
+		if !isLintRule(rule) {
+			l.report(LINT_DIRECTIVE, at, fmt.Sprintf("Unknown lint rule '%s'.", rule), suggest(rule, LintRules))
+		}
+	}
+	l.directives = append(l.directives, directive)
+}
+
+func isLintRule(rule string) bool {
+	for _, known := range LintRules {
+		if known == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// enabled dice si rule esta activa en line segun las directivas anteriores.
+func (l *Linter) enabled(rule string, line int) bool {
+	enabled := true
+	for _, directive := range l.directives {
+		if !directive.covers(rule) {
+			continue
+		}
+		switch directive.action {
+		case "disable":
+			if directive.line <= line {
+				enabled = false
+			}
+		case "enable":
+			if directive.line <= line {
+				enabled = true
+			}
+		case "disable-line":
+			if directive.line == line {
+				return false
+			}
+		case "disable-next-line":
+			if directive.line+1 == line {
+				return false
+			}
+		}
+	}
+	return enabled
+}
+
+func (l *Linter) warn(rule string, token Token, message string, hints ...string) {
+	if l.enabled(rule, token.Line) {
+		l.report(rule, token, message, hints...)
+	}
+}
+
+func (l *Linter) report(rule string, token Token, message string, hints ...string) {
+	diagnostic := Diagnostic{
+		Severity: SEVERITY_WARNING,
+		Code:     rule,
+		Message:  message,
+		File:     l.file,
+		Range:    tokenRange(token.Line, token.Column, utf8.RuneCountInString(token.Lexeme)),
+	}
+	text := fmt.Sprintf("[line %d] Warning at '%s': %s", token.Line, token.Lexeme, message)
+	for _, hint := range hints {
+		if hint != "" {
+			diagnostic.Hints = append(diagnostic.Hints, hint)
+			text += " " + hint
+		}
+	}
+	diagnostic.text = text + " (" + rule + ")"
+	l.Diagnostics = append(l.Diagnostics, diagnostic)
+}
+
+func (l *Linter) Lint(stmts []Stmt) []Diagnostic {
+	// Como en el Resolver, las globales se ven desde antes de declararse.
+	for _, stmt := range stmts {
+		switch s := stmt.(type) {
+		case Var:
+			l.declare(s.Name, "Variable")
+		case MultiVar:
+			for _, name := range s.Names {
+				l.declare(name, "Variable")
+			}
+		case Function:
+			l.declare(s.Name, "Function")
+		case Enum:
+			l.declare(s.Name, "Enum")
+		case Class:
+			l.declare(s.Name, "Class")
+		}
+	}
+	l.lintStmts(stmts)
+
+	sort.SliceStable(l.Diagnostics, func(a, b int) bool {
+		start, other := l.Diagnostics[a].Range.Start, l.Diagnostics[b].Range.Start
+		if start.Line != other.Line {
+			return start.Line < other.Line
+		}
+		return start.Column < other.Column
+	})
+	return l.Diagnostics
+}
+
+// lintStmts recorre una lista de sentencias y avisa, una sola vez, del
+// codigo que queda despues de un return o un throw.
+func (l *Linter) lintStmts(stmts []Stmt) {
+	reported := false
+	for index, stmt := range stmts {
+		l.lintStmt(stmt)
+		if reported || index+1 == len(stmts) || stmts[index+1] == nil {
+			continue
+		}
+		if keyword, ok := terminator(stmt); ok {
+			at, ok := stmtToken(stmts[index+1])
+			if !ok {
+				at = keyword
+			}
+			l.warn(LINT_UNREACHABLE, at, fmt.Sprintf("Unreachable code after '%s'.", keyword.Lexeme))
+			reported = true
+		}
+	}
+}
+
+func (l *Linter) lintStmt(stmt Stmt) {
+	if stmt == nil {
+		return
+	}
+	stmt.AcceptStmt(l)
+}
+
+func (l *Linter) lintExpr(expr Expr) {
+	if expr == nil {
+		return
+	}
+	expr.AcceptExpr(l)
+}
+
+func (l *Linter) lintExprs(exprs []Expr) {
+	for _, expr := range exprs {
+		l.lintExpr(expr)
+	}
+}
+
+func (l *Linter) lintSelectors(selectors [][]Expr) {
+	for _, selector := range selectors {
+		l.lintExprs(selector)
+	}
+}
+
+func (l *Linter) beginScope() {
+	l.scopes = append(l.scopes, make(map[string]*lintBinding))
+}
+
+func (l *Linter) endScope() {
+	for _, binding := range l.scopes[len(l.scopes)-1] {
+		l.checkUnused(binding)
+	}
+	l.scopes = l.scopes[:len(l.scopes)-1]
+}
+
+// checkUnused avisa de las variables locales que nunca se leen; las que
+// empiezan con "_" se ignoran a proposito.
+func (l *Linter) checkUnused(binding *lintBinding) {
+	if binding.used || strings.HasPrefix(binding.name.Lexeme, "_") {
+		return
+	}
+	if binding.kind == "Parameter" {
+		l.warn(LINT_UNUSED_PARAMETER, binding.name, fmt.Sprintf("Parameter '%s' is never used.", binding.name.Lexeme))
+		return
+	}
+	l.warn(LINT_UNUSED_VARIABLE, binding.name, fmt.Sprintf("%s '%s' is declared but never used.", binding.kind, binding.name.Lexeme))
+}
+
+func (l *Linter) declare(name Token, kind string) {
+	// Los nombres internos del parser (subfx-..., submap-...) no son del usuario.
+	if strings.Contains(name.Lexeme, "-") {
+		return
+	}
+	if len(l.scopes) == 0 {
+		if _, ok := l.globals[name.Lexeme]; !ok {
+			l.globals[name.Lexeme] = &lintBinding{name: name, kind: kind}
+		}
+		return
+	}
+	if shadowed := l.lookupOuter(name.Lexeme); shadowed != nil {
+		l.warn(LINT_SHADOW, name, fmt.Sprintf("%s '%s' shadows the %s declared on line %d.",
+			kind, name.Lexeme, strings.ToLower(shadowed.kind), shadowed.name.Line))
+	}
+	l.scopes[len(l.scopes)-1][name.Lexeme] = &lintBinding{name: name, kind: kind}
+}
+
+func (l *Linter) lookup(name string) *lintBinding {
+	for i := len(l.scopes) - 1; i >= 0; i-- {
+		if binding, ok := l.scopes[i][name]; ok {
+			return binding
+		}
+	}
+	return l.globals[name]
+}
+
+// lookupOuter busca name en los scopes locales que encierran al actual; las
+// globales no cuentan, que un parametro se llame como una es lo normal.
+func (l *Linter) lookupOuter(name string) *lintBinding {
+	for i := len(l.scopes) - 2; i >= 0; i-- {
+		if binding, ok := l.scopes[i][name]; ok && binding.kind != "" {
+			return binding
+		}
+	}
+	return nil
+}
+
+func (l *Linter) use(name Token) {
+	if binding := l.lookup(name.Lexeme); binding != nil {
+		binding.used = true
+	}
+}
+
+// assign revisa una escritura a name: Enviroment.Assign ignora en silencio
+// los nombres que no existen.
+func (l *Linter) assign(name Token) {
+	if l.lookup(name.Lexeme) != nil || GlobalFx[name.Lexeme] != nil {
+		return
+	}
+	l.warn(LINT_UNDECLARED_ASSIGNMENT, name, fmt.Sprintf("Assignment to undeclared variable '%s'.", name.Lexeme),
+		suggest(name.Lexeme, l.visibleNames()))
+}
+
+// lintTarget recorre el destino de una asignacion: una variable sola se
+// escribe pero no se lee.
+func (l *Linter) lintTarget(target Expr) {
+	if variable, ok := target.(Var); ok && !variable.Sub {
+		l.assign(variable.Name)
+		return
+	}
+	l.lintExpr(target)
+}
+
+func (l *Linter) visibleNames() []string {
+	names := []string{}
+	for _, scope := range l.scopes {
+		for name := range scope {
+			names = append(names, name)
+		}
+	}
+	for name := range l.globals {
+		names = append(names, name)
+	}
+	for name := range GlobalFx {
+		names = append(names, name)
+	}
+	return names
+}
+
+func (l *Linter) lintFunction(function Function) {
+	l.beginScope()
+	l.scopes[len(l.scopes)-1]["this"] = &lintBinding{name: Token{Lexeme: "this"}, used: true}
+	for _, param := range function.Parameters {
+		l.declare(param, "Parameter")
+	}
+	l.lintStmts(function.Body)
+	l.endScope()
+}
+
+// checkCondition avisa de los if y while cuya condicion no depende de nada.
+// while (true) y for (;;) son la forma de escribir un ciclo infinito.
+func (l *Linter) checkCondition(keyword Token, condition Expr) {
+	if !isConstant(condition) {
+		return
+	}
+	literal, ok := condition.(Literal)
+	if !ok {
+		l.warn(LINT_CONSTANT_CONDITION, keyword, "Condition is constant.")
+		return
+	}
+	truthy := literal.Value != nil && literal.Value != false
+	if truthy && keyword.Type != IF {
+		return
+	}
+	l.warn(LINT_CONSTANT_CONDITION, keyword, fmt.Sprintf("Condition is always %t.", truthy))
+}
+
+func isConstant(expr Expr) bool {
+	switch e := expr.(type) {
+	case Literal:
+		return true
+	case Grouping:
+		return isConstant(e.Expression)
+	case Unary:
+		return isConstant(e.Value)
+	case Binary:
+		return isConstant(e.Left) && isConstant(e.Right)
+	case Logical:
+		return isConstant(e.Left) && isConstant(e.Right)
+	}
+	return false
+}
+
+// targetName devuelve "a.b.c" para variables y cadenas de propiedades, o ""
+// si la expresion es otra cosa.
+func targetName(expr Expr) string {
+	switch e := expr.(type) {
+	case Var:
+		if !e.Sub {
+			return e.Name.Lexeme
+		}
+	case Get:
+		if object := targetName(e.Object); object != "" && !e.Optional {
+			return object + "." + e.Name.Lexeme
+		}
+	case Grouping:
+		return targetName(e.Expression)
+	}
+	return ""
+}
+
+// terminator devuelve el return o throw despues del cual stmt no sigue.
+func terminator(stmt Stmt) (Token, bool) {
+	switch s := stmt.(type) {
+	case Return:
+		return s.Keyword, true
+	case Throw:
+		return s.Keyword, true
+	case Block:
+		for _, inner := range s.Statements {
+			if keyword, ok := terminator(inner); ok {
+				return keyword, true
+			}
+		}
+	case If:
+		if s.ElseBranch == nil {
+			return Token{}, false
+		}
+		keyword, ok := terminator(s.ThenBranch)
+		if _, other := terminator(s.ElseBranch); ok && other {
+			return keyword, true
+		}
+	}
+	return Token{}, false
+}
+
+// stmtToken y exprToken buscan un token para ubicar una sentencia.
+func stmtToken(stmt Stmt) (Token, bool) {
+	switch s := stmt.(type) {
+	case Expression:
+		return exprToken(s.Expression)
+	case Var:
+		return s.Name, true
+	case MultiVar:
+		return s.Names[0], true
+	case Function:
+		return s.Name, true
+	case Class:
+		return s.Name, true
+	case Enum:
+		return s.Name, true
+	case Extend:
+		return s.Keyword, true
+	case If:
+		return s.Keyword, true
+	case While:
+		return s.Keyword, true
+	case ForIn:
+		return s.Name, true
+	case Return:
+		return s.Keyword, true
+	case Yield:
+		return s.Keyword, true
+	case Try:
+		return s.Keyword, true
+	case Throw:
+		return s.Keyword, true
+	case PrintStmt:
+		return s.Keyword, true
+	case Block:
+		for _, inner := range s.Statements {
+			if token, ok := stmtToken(inner); ok {
+				return token, true
+			}
+		}
+	}
+	return Token{}, false
+}
+
+func exprToken(expr Expr) (Token, bool) {
+	switch e := expr.(type) {
+	case Var:
+		return e.Name, true
+	case Assign:
+		return e.Name, true
+	case Call:
+		return exprToken(e.Callee)
+	case Get:
+		return exprToken(e.Object)
+	case Index:
+		return exprToken(e.Object)
+	case Set:
+		return exprToken(e.Object)
+	case Binary:
+		if token, ok := exprToken(e.Left); ok {
+			return token, true
+		}
+		return e.Operator, true
+	case Logical:
+		if token, ok := exprToken(e.Left); ok {
+			return token, true
+		}
+		return e.Operator, true
+	case Unary:
+		return e.Operator, true
+	case CompoundAssign:
+		return exprToken(e.Target)
+	case Increment:
+		return e.Operator, true
+	case MultiAssign:
+		return exprToken(e.Targets[0])
+	case TupleLiteral:
+		return e.Paren, true
+	case SetLiteral:
+		return e.Brace, true
+	case Match:
+		return e.Keyword, true
+	case Super:
+		return e.Keyword, true
+	case Grouping:
+		return exprToken(e.Expression)
+	case GroupingABS:
+		return e.Pipe, true
+	}
+	return Token{}, false
+}
+
+func (l *Linter) VisitBlockStmt(stmt Block) interface{} {
+	l.beginScope()
+	l.lintStmts(stmt.Statements)
+	l.endScope()
+	return nil
+}
+
+func (l *Linter) VisitVar(stmt Var) interface{} {
+	l.lintExpr(stmt.InitializerVal)
+	l.lintExprs(stmt.InitializerArray)
+	for _, item := range stmt.InitializerMap {
+		l.lintExpr(item.Key)
+		l.lintExpr(item.Value)
+	}
+	if function, ok := stmt.InitializerFx.(Function); ok {
+		l.lintFunction(function)
+	}
+	l.declare(stmt.Name, "Variable")
+	return nil
+}
+
+func (l *Linter) VisitVariableExpr(expr Var) interface{} {
+	if expr.Sub {
+		return l.VisitVar(expr)
+	}
+	l.use(expr.Name)
+	return nil
+}
+
+func (l *Linter) VisitMultiVarStmt(stmt MultiVar) interface{} {
+	l.lintExpr(stmt.Value)
+	for _, name := range stmt.Names {
+		l.declare(name, "Variable")
+	}
+	return nil
+}
+
+func (l *Linter) VisitAssignExpr(expr Assign) interface{} {
+	l.lintExpr(expr.Value)
+	l.lintSelectors(expr.Selectors)
+	if len(expr.Selectors) > 0 {
+		// a[i] = v modifica el valor de a, asi que cuenta como uso.
+		l.use(expr.Name)
+	}
+	l.assign(expr.Name)
+	return nil
+}
+
+func (l *Linter) VisitMultiAssignExpr(expr MultiAssign) interface{} {
+	l.lintExpr(expr.Value)
+	for _, target := range expr.Targets {
+		l.lintTarget(target)
+	}
+	return nil
+}
+
+func (l *Linter) VisitCompoundAssignExpr(expr CompoundAssign) interface{} {
+	l.lintExpr(expr.Value)
+	l.lintTarget(expr.Target)
+	return nil
+}
+
+func (l *Linter) VisitIncrementExpr(expr Increment) interface{} {
+	l.lintTarget(expr.Target)
+	return nil
+}
+
+func (l *Linter) VisitFunctionStmt(stmt Function) interface{} {
+	l.declare(stmt.Name, "Function")
+	l.lintFunction(stmt)
+	return nil
+}
+
+func (l *Linter) VisitExpressionStmt(stmt Expression) interface{} {
+	l.lintExpr(stmt.Expression)
+	return nil
+}
+
+func (l *Linter) VisitPrintStmt(stmt PrintStmt) interface{} {
+	l.lintExpr(stmt.Expression)
+	return nil
+}
+
+func (l *Linter) VisitIfStmt(stmt If) interface{} {
+	l.lintExpr(stmt.Condition)
+	l.checkCondition(stmt.Keyword, stmt.Condition)
+	l.lintStmt(stmt.ThenBranch)
+	l.lintStmt(stmt.ElseBranch)
+	return nil
+}
+
+func (l *Linter) VisitWhileStmt(stmt While) interface{} {
+	l.lintExpr(stmt.Condition)
+	l.checkCondition(stmt.Keyword, stmt.Condition)
+	l.lintStmt(stmt.Body)
+	return nil
+}
+
+func (l *Linter) VisitReturnStmt(stmt Return) interface{} {
+	l.lintExpr(stmt.Value)
+	return nil
+}
+
+func (l *Linter) VisitYieldStmt(stmt Yield) interface{} {
+	l.lintExpr(stmt.Value)
+	return nil
+}
+
+func (l *Linter) VisitTryStmt(stmt Try) interface{} {
+	l.VisitBlockStmt(Block{Statements: stmt.Body})
+	if stmt.Catch != nil {
+		l.beginScope()
+		if stmt.CatchName != nil {
+			l.declare(*stmt.CatchName, "Variable")
+		}
+		l.lintStmts(stmt.Catch)
+		l.endScope()
+	}
+	if stmt.Finally != nil {
+		l.VisitBlockStmt(Block{Statements: stmt.Finally})
+	}
+	return nil
+}
+
+func (l *Linter) VisitThrowStmt(stmt Throw) interface{} {
+	l.lintExpr(stmt.Value)
+	return nil
+}
+
+func (l *Linter) VisitForInStmt(stmt ForIn) interface{} {
+	l.lintExpr(stmt.Iterable)
+	l.beginScope()
+	l.declare(stmt.Name, "Variable")
+	l.lintStmt(stmt.Body)
+	l.endScope()
+	return nil
+}
+
+func (l *Linter) VisitEnumStmt(stmt Enum) interface{} {
+	for _, member := range stmt.Members {
+		l.lintExpr(member.Value)
+	}
+	l.declare(stmt.Name, "Enum")
+	return nil
+}
+
+func (l *Linter) VisitExtendStmt(stmt Extend) interface{} {
+	for _, method := range stmt.Methods {
+		l.lintFunction(method)
+	}
+	return nil
+}
+
+func (l *Linter) VisitClassStmt(stmt Class) interface{} {
+	l.declare(stmt.Name, "Class")
+	if stmt.Superclass != nil {
+		l.use(stmt.Superclass.Name)
+	}
+	for _, method := range stmt.Methods {
+		l.lintFunction(method)
+	}
+	return nil
+}
+
+func (l *Linter) VisitMatchExpr(expr Match) interface{} {
+	l.lintExpr(expr.Subject)
+	for _, arm := range expr.Arms {
+		l.beginScope()
+		l.lintPattern(arm.Pattern)
+		l.lintExpr(arm.Guard)
+		l.lintExpr(arm.Body)
+		l.endScope()
+	}
+	return nil
+}
+
+func (l *Linter) lintPattern(pattern Pattern) {
+	switch p := pattern.(type) {
+	case ValuePattern:
+		l.lintExpr(p.Value)
+	case BindingPattern:
+		l.declare(p.Name, "Variable")
+	case ArrayPattern:
+		for _, element := range p.Elements {
+			l.lintPattern(element)
+		}
+		if p.Rest != nil {
+			l.declare(*p.Rest, "Variable")
+		}
+	case MapPattern:
+		for _, entry := range p.Entries {
+			l.lintPattern(entry.Pattern)
+		}
+	}
+}
+
+var comparisons = map[TokenType]bool{
+	EQUAL_EQUAL:   true,
+	BANG_EQUAL:    true,
+	LESS:          true,
+	LESS_EQUAL:    true,
+	GREATER:       true,
+	GREATER_EQUAL: true,
+}
+
+func (l *Linter) VisitBinaryExpr(expr Binary) interface{} {
+	l.lintExpr(expr.Left)
+	l.lintExpr(expr.Right)
+	if name := targetName(expr.Left); comparisons[expr.Operator.Type] && name != "" && name == targetName(expr.Right) {
+		l.warn(LINT_SELF_COMPARISON, expr.Operator, fmt.Sprintf("Comparison of '%s' with itself.", name))
+	}
+	return nil
+}
+
+func (l *Linter) VisitLogicalExpr(expr Logical) interface{} {
+	l.lintExpr(expr.Left)
+	l.lintExpr(expr.Right)
+	return nil
+}
+
+func (l *Linter) VisitUnaryExpr(expr Unary) interface{} {
+	l.lintExpr(expr.Value)
+	return nil
+}
+
+func (l *Linter) VisitGroupingExpr(expr Grouping) interface{} {
+	l.lintExpr(expr.Expression)
+	return nil
+}
+
+func (l *Linter) VisitGroupingABSExpr(expr GroupingABS) interface{} {
+	l.lintExpr(expr.Expression)
+	return nil
+}
+
+func (l *Linter) VisitLiteralExpr(expr Literal) interface{} {
+	switch value := expr.Value.(type) {
+	case []Expr:
+		l.lintExprs(value)
+	case []ItemVar:
+		for _, item := range value {
+			l.lintExpr(item.Key)
+			l.lintExpr(item.Value)
+		}
+	}
+	return nil
+}
+
+func (l *Linter) VisitCallExpr(expr Call) interface{} {
+	l.lintExpr(expr.Callee)
+	l.lintExprs(expr.Arguments)
+	return nil
+}
+
+func (l *Linter) VisitGetExpr(expr Get) interface{} {
+	l.lintExpr(expr.Object)
+	return nil
+}
+
+func (l *Linter) VisitIndexExpr(expr Index) interface{} {
+	l.lintExpr(expr.Object)
+	l.lintExprs(expr.Indexes)
+	return nil
+}
+
+func (l *Linter) VisitSetExpr(expr Set) interface{} {
+	l.lintExpr(expr.Value)
+	l.lintExpr(expr.Object)
+	l.lintSelectors(expr.Selectors)
+	return nil
+}
+
+func (l *Linter) VisitTupleExpr(expr TupleLiteral) interface{} {
+	l.lintExprs(expr.Elements)
+	return nil
+}
+
+func (l *Linter) VisitSetLiteralExpr(expr SetLiteral) interface{} {
+	l.lintExprs(expr.Elements)
+	return nil
+}
+
+func (l *Linter) VisitSuperExpr(expr Super) interface{} {
+	return nil
+}
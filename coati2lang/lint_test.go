@@ -0,0 +1,126 @@
+package coati2lang
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// lintResults corre el linter y resume cada diagnostico como
+// "linea:columna regla: mensaje".
+func lintResults(t *testing.T, source string, strict bool) []string {
+	t.Helper()
+	diagnostics, errs := Lint("test.lox", source, strict)
+	if len(errs) > 0 {
+		t.Fatalf("%s: %v", source, errs)
+	}
+	results := []string{}
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity != SEVERITY_WARNING {
+			t.Errorf("%s: severity %s, want %s", source, diagnostic.Severity, SEVERITY_WARNING)
+		}
+		start := diagnostic.Range.Start
+		results = append(results, fmt.Sprintf("%d:%d %s: %s", start.Line, start.Column, diagnostic.Code, diagnostic.Message))
+	}
+	return results
+}
+
+func TestLintRules(t *testing.T) {
+	cases := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{"clean", "fun sum(a, b) { return a + b; }\nprintln(sum(1, 2));", nil},
+		{"unused variable", "fun f() {\n  var a = 1;\n}\nf();", []string{
+			"2:7 unused-variable: Variable 'a' is declared but never used.",
+		}},
+		{"underscore is never unused", "fun f(_a) {\n  var _b = 1;\n}\nf(1);", nil},
+		{"unused parameter", "fun f(a) {\n  return 1;\n}\nf(1);", []string{
+			"1:7 unused-parameter: Parameter 'a' is never used.",
+		}},
+		{"shadow", "fun f() {\n  var a = 1;\n  {\n    var a = 2;\n    println(a);\n  }\n  println(a);\n}\nf();", []string{
+			"4:9 shadow: Variable 'a' shadows the variable declared on line 2.",
+		}},
+		{"unreachable", "fun f() {\n  return 1;\n  println(2);\n}\nf();", []string{
+			"3:3 unreachable: Unreachable code after 'return'.",
+		}},
+		{"unreachable after throw", "fun f() {\n  throw \"x\";\n  println(2);\n}\nf();", []string{
+			"3:3 unreachable: Unreachable code after 'throw'.",
+		}},
+		{"undeclared assignment", "var count = 0;\ncuont = 1;\nprintln(count);", []string{
+			"2:1 undeclared-assignment: Assignment to undeclared variable 'cuont'.",
+		}},
+		{"self comparison", "var a = 1;\nprintln(a == a);", []string{
+			"2:11 self-comparison: Comparison of 'a' with itself.",
+		}},
+		{"constant condition", "if (true) println(1);\nwhile (false) println(2);\nif (1 < 2) println(3);", []string{
+			"1:1 constant-condition: Condition is always true.",
+			"2:1 constant-condition: Condition is always false.",
+			"3:1 constant-condition: Condition is constant.",
+		}},
+		{"infinite loop", "fun f() {\n  while (true) {\n    return 1;\n  }\n}\nf();", nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := lintResults(t, c.source, false)
+			if len(c.want) == 0 && len(got) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("%s\ngot  %q\nwant %q", c.source, got, c.want)
+			}
+		})
+	}
+}
+
+func TestLintDirectives(t *testing.T) {
+	cases := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{"disable-line", "var a = 1;\nprintln(a == a); // lint:disable-line self-comparison -- adrede", nil},
+		{"disable-next-line", "var a = 1;\n// lint:disable-next-line self-comparison\nprintln(a == a);\nprintln(a == a);", []string{
+			"4:11 self-comparison: Comparison of 'a' with itself.",
+		}},
+		{"disable and enable", "// lint:disable constant-condition\nif (true) println(1);\n// lint:enable constant-condition\nif (true) println(2);", []string{
+			"4:1 constant-condition: Condition is always true.",
+		}},
+		{"disable every rule", "// lint:disable\nif (true) println(1);\nvar a = 1;\nprintln(a == a);", nil},
+		{"other rules still apply", "var a = 1;\nif (a == a) println(1); // lint:disable-line constant-condition", []string{
+			"2:7 self-comparison: Comparison of 'a' with itself.",
+		}},
+		{"unknown rule", "// lint:disable self-comparisons\nvar a = 1;", []string{
+			"1:4 lint-directive: Unknown lint rule 'self-comparisons'.",
+		}},
+		{"missing action", "var a = 1; //   lint: -- nada\nprintln(a);", []string{
+			"1:17 lint-directive: Expect an action after 'lint:'.",
+		}},
+		{"unknown action", "// lint:silence shadow\nvar a = 1;", []string{
+			"1:4 lint-directive: Unknown lint action 'silence'.",
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := lintResults(t, c.source, false)
+			if len(c.want) == 0 && len(got) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("%s\ngot  %q\nwant %q", c.source, got, c.want)
+			}
+		})
+	}
+}
+
+func TestLintStrict(t *testing.T) {
+	got := lintResults(t, "var a = 1;\nprint a == a;", true)
+	want := []string{"2:9 self-comparison: Comparison of 'a' with itself."}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if _, errs := Lint("test.lox", "print 1", true); len(errs) == 0 {
+		t.Error("expected a syntax error")
+	}
+}
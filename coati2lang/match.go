@@ -0,0 +1,75 @@
+package coati2lang
+
+func (i *Interpreter) VisitMatchExpr(expr Match) interface{} {
+	value := i.full_evaluate(expr.Subject)
+
+	for _, arm := range expr.Arms {
+		enviroment := NewEnviroment(i.enviroment)
+		if !i.matchPattern(arm.Pattern, value, enviroment) {
+			continue
+		}
+		if arm.Guard != nil && !i.isTruthy(i.evaluateIn(arm.Guard, enviroment)) {
+			continue
+		}
+		return i.evaluateIn(arm.Body, enviroment)
+	}
+
+	raise(expr.Keyword, MATCH_ERROR, "No match arm for value '%v'.", value)
+	return nil
+}
+
+func (i *Interpreter) evaluateIn(expr Expr, enviroment *Enviroment) interface{} {
+	previous := i.enviroment
+	defer func() {
+		i.enviroment = previous
+	}()
+	i.enviroment = enviroment
+	return i.full_evaluate(expr)
+}
+
+func (i *Interpreter) matchPattern(pattern Pattern, value interface{}, enviroment *Enviroment) bool {
+	switch p := pattern.(type) {
+	case WildcardPattern:
+		return true
+	case LiteralPattern:
+		return i.isEqual(p.Value, value)
+	case ValuePattern:
+		// El resolver resolvio el valor dentro del scope del brazo.
+		return i.isEqual(i.evaluateIn(p.Value, enviroment), value)
+	case BindingPattern:
+		enviroment.Define(p.Name.Lexeme, value)
+		return true
+	case ArrayPattern:
+		array, ok := thaw(value).([]interface{})
+		if !ok {
+			return false
+		}
+		if len(array) < len(p.Elements) || (p.Rest == nil && len(array) != len(p.Elements)) {
+			return false
+		}
+		for index, element := range p.Elements {
+			if !i.matchPattern(element, array[index], enviroment) {
+				return false
+			}
+		}
+		if p.Rest != nil {
+			rest := make([]interface{}, len(array)-len(p.Elements))
+			copy(rest, array[len(p.Elements):])
+			enviroment.Define(p.Rest.Lexeme, NewLoxArray(rest))
+		}
+		return true
+	case MapPattern:
+		m, ok := thaw(value).(map[interface{}]interface{})
+		if !ok {
+			return false
+		}
+		for _, entry := range p.Entries {
+			item, ok := m[entry.Key]
+			if !ok || !i.matchPattern(entry.Pattern, item, enviroment) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,19 @@
+package coati2lang
+
+import "testing"
+
+func TestMatchValuePatternLocals(t *testing.T) {
+	cases := []struct {
+		source string
+		want   string
+	}{
+		{`fun f() { var o = {v: 1}; return match (1) { o.v => "yes", _ => "no" }; } var result = f();`, "yes"},
+		{`fun f() { enum Dir { N, S } return match (Dir.S) { Dir.N => "north", Dir.S => "south", _ => "other" }; } var result = f();`, "south"},
+		{`var limit = 3; var result = match (3) { limit => "global", _ => "no" };`, "global"},
+	}
+	for _, c := range cases {
+		if got := runSandboxed(t, c.source, nil); got != c.want {
+			t.Errorf("%s\ngot  %v\nwant %s", c.source, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,415 @@
+package coati2lang
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+type MethodFx func(interpreter *Interpreter, this interface{}, args []interface{}) interface{}
+
+// Method es un metodo nativo. Si tiene Signature se valida con ella y
+// Arity se ignora; Arity queda para los metodos registrados sin firma.
+type Method struct {
+	Arity     int
+	Signature *Signature
+	Fx        MethodFx
+	// Mutates marca los metodos que modifican el receptor; no se pueden
+	// llamar sobre tuplas ni mapas congelados.
+	Mutates bool
+}
+
+var (
+	ARRAY_FX_MAP = map[string]Method{
+		"len":      {Signature: sig("() -> number", "Number of elements."), Fx: arrayLen},
+		"push":     {Signature: sig("(...values: any) -> number", "Appends values; returns the new length."), Fx: arrayPush, Mutates: true},
+		"pop":      {Signature: sig("() -> any", "Removes and returns the last element."), Fx: arrayPop, Mutates: true},
+		"first":    {Signature: sig("() -> any", "First element, or nil."), Fx: arrayFirst},
+		"last":     {Signature: sig("() -> any", "Last element, or nil."), Fx: arrayLast},
+		"join":     {Signature: sig("(sep: any) -> string", "Joins the elements with sep."), Fx: arrayJoin},
+		"indexOf":  {Signature: sig("(value: any) -> number", "Index of value, or -1."), Fx: arrayIndexOf},
+		"contains": {Signature: sig("(value: any) -> boolean", "Whether value is an element."), Fx: arrayContains},
+		"reverse":  {Signature: sig("() -> array", "Reversed copy."), Fx: arrayReverse},
+		"slice":    {Signature: sig("(start: number, end: number) -> array", "Elements from start up to end; negative indexes count from the end."), Fx: arraySlice},
+		"map":      {Signature: sig("(fn: callable) -> array", "fn(item, index) for each element."), Fx: arrayMap},
+		"filter":   {Signature: sig("(fn: callable) -> array", "Elements where fn(item, index) is truthy."), Fx: arrayFilter},
+		"reduce":   {Signature: sig("(fn: callable, initial: any) -> any", "Folds fn(acc, item, index) starting at initial."), Fx: arrayReduce},
+		"sort":     {Signature: sig("() -> array", "Sorted copy."), Fx: arraySort},
+	}
+
+	MAP_FX_MAP = map[string]Method{
+		"len":    {Signature: sig("() -> number", "Number of keys."), Fx: mapLen},
+		"keys":   {Signature: sig("() -> array", "Keys of the map."), Fx: mapKeys},
+		"values": {Signature: sig("() -> array", "Values of the map."), Fx: mapValues},
+		"has":    {Signature: sig("(key: any) -> boolean", "Whether key is present."), Fx: mapHas},
+		"remove": {Signature: sig("(key: any) -> any", "Removes key and returns its value."), Fx: mapRemove, Mutates: true},
+	}
+
+	NUMBER_FX_MAP = map[string]Method{
+		"round":  {Signature: sig("(digits?: number) -> number", "Rounds to digits decimals (0 by default)."), Fx: numberRound},
+		"floor":  {Signature: sig("() -> number", "Largest integer not greater than the number."), Fx: numberFloor},
+		"ceil":   {Signature: sig("() -> number", "Smallest integer not less than the number."), Fx: numberCeil},
+		"abs":    {Signature: sig("() -> number", "Absolute value."), Fx: numberAbs},
+		"string": {Signature: sig("() -> string", "The number as a string."), Fx: valueString},
+	}
+
+	BOOLEAN_FX_MAP = map[string]Method{
+		"string": {Signature: sig("() -> string", "The boolean as a string."), Fx: valueString},
+	}
+
+	METHODS = map[string]map[string]Method{
+		"string":  STRING_FX_MAP,
+		"array":   ARRAY_FX_MAP,
+		"map":     MAP_FX_MAP,
+		"number":  NUMBER_FX_MAP,
+		"boolean": BOOLEAN_FX_MAP,
+	}
+)
+
+// RegisterMethod agrega (o reemplaza) un metodo nativo para un tipo.
+func RegisterMethod(typeName string, name string, method Method) {
+	if METHODS[typeName] == nil {
+		METHODS[typeName] = make(map[string]Method)
+	}
+	METHODS[typeName][name] = method
+}
+
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "nil"
+	case float64, int, int64:
+		return "number"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case *LoxArray:
+		return "array"
+	case Tuple:
+		return "tuple"
+	case map[interface{}]interface{}, FrozenMap:
+		return "map"
+	case *LoxSet:
+		return "set"
+	case *Generator:
+		return "generator"
+	case *LoxEnum, *LoxEnumMember:
+		return "enum"
+	case *LoxClass:
+		return "class"
+	case *LoxInstance:
+		return "object"
+	case LoxCallable:
+		return "function"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func (i *Interpreter) findMethod(receiver interface{}, name string) (LoxCallable, *Method) {
+	kind := typeName(receiver)
+	if kind == "tuple" {
+		// Las tuplas comparten los metodos de solo lectura de los arrays.
+		kind = "array"
+	}
+	if method, ok := METHODS[kind][name]; ok {
+		return nil, &method
+	}
+	if method, ok := i.extensions[kind][name]; ok {
+		return method, nil
+	}
+	return nil, nil
+}
+
+// callMethod resuelve llamadas receptor.nombre(...) sobre los tipos nativos.
+// Devuelve false cuando la llamada debe seguir el camino normal (propiedades
+// de mapas y objetos).
+func (i *Interpreter) callMethod(expr Call, target Get, receiver interface{}) (interface{}, bool) {
+	name := target.Name.Lexeme
+	if object, ok := thaw(receiver).(map[interface{}]interface{}); ok {
+		if _, ok := object[name]; ok {
+			return nil, false
+		}
+		if _, ok := i.operatorMethod(object, "__index"); ok {
+			return nil, false
+		}
+	}
+	if _, ok := receiver.(LoxObject); ok {
+		return nil, false
+	}
+
+	function, native := i.findMethod(receiver, name)
+	if function == nil && native == nil {
+		kind := typeName(receiver)
+		if kind == "tuple" {
+			kind = "array"
+		}
+		raiseHint(target.Name, NAME_ERROR, suggest(name, i.methodNames(kind)), "No method '%s' on type '%s'.", name, typeName(receiver))
+	}
+
+	arguments := []interface{}{}
+	for _, argument := range expr.Arguments {
+		arguments = append(arguments, i.full_evaluate(argument))
+	}
+
+	if function != nil {
+		if function.Arity() != len(arguments) {
+			raise(expr.Paren, ARGUMENT_ERROR, "Expected %d arguments but got %d.", function.Arity(), len(arguments))
+		}
+		return i.callFrame(typeName(receiver)+"."+name, expr.Paren, func() interface{} {
+			return function.Call(i, arguments, receiver)
+		}), true
+	}
+
+	if native.Signature != nil {
+		native.Signature.check(expr.Paren, typeName(receiver)+"."+name, arguments)
+	} else if native.Arity != -1 && native.Arity != len(arguments) {
+		raise(expr.Paren, ARGUMENT_ERROR, "Expected %d arguments but got %d.", native.Arity, len(arguments))
+	}
+	if native.Mutates && isFrozen(receiver) {
+		raise(target.Name, TYPE_ERROR, "Can't call '%s' on an immutable %s.", name, typeName(receiver))
+	}
+	// Los metodos que modifican reciben el valor original; el resto, la
+	// vista de lectura.
+	this := receiver
+	if !native.Mutates {
+		this = thaw(receiver)
+	}
+	result := native.Fx(i, this, arguments)
+	return i.charge(expr.Paren, result), true
+}
+
+func (i *Interpreter) VisitExtendStmt(stmt Extend) interface{} {
+	kind := stmt.Type.Lexeme
+	if i.extensions[kind] == nil {
+		i.extensions[kind] = make(map[string]LoxCallable)
+	}
+	for _, method := range stmt.Methods {
+		i.extensions[kind][method.Name.Lexeme] = Function{
+			Name:        method.Name,
+			Parameters:  method.Parameters,
+			Body:        method.Body,
+			Closure:     i.enviroment,
+			IsGenerator: method.IsGenerator,
+		}
+	}
+	return nil
+}
+
+// invoke llama a un callback pasando solo los argumentos que acepta.
+func (i *Interpreter) invoke(callable LoxCallable, arguments ...interface{}) interface{} {
+	if arity := callable.Arity(); arity >= 0 {
+		for len(arguments) < arity {
+			arguments = append(arguments, nil)
+		}
+		arguments = arguments[:arity]
+	}
+	return i.callFrame(callableName(callable), Token{}, func() interface{} {
+		return callable.Call(i, arguments, nil)
+	})
+}
+
+func arrayLen(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	return float64(len(this.([]interface{})))
+}
+
+func arrayPush(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	interpreter.allocate(Token{}, int64(len(args))*ELEMENT_SIZE)
+	array := this.(*LoxArray)
+	array.Elements = append(array.Elements, args...)
+	return float64(len(array.Elements))
+}
+
+func arrayPop(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	array := this.(*LoxArray)
+	if len(array.Elements) == 0 {
+		return nil
+	}
+	last := array.Elements[len(array.Elements)-1]
+	array.Elements[len(array.Elements)-1] = nil
+	array.Elements = array.Elements[:len(array.Elements)-1]
+	return last
+}
+
+func arrayFirst(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	array := this.([]interface{})
+	if len(array) == 0 {
+		return nil
+	}
+	return array[0]
+}
+
+func arrayLast(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	array := this.([]interface{})
+	if len(array) == 0 {
+		return nil
+	}
+	return array[len(array)-1]
+}
+
+func arrayJoin(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	array := this.([]interface{})
+	parts := make([]string, len(array))
+	for index, item := range array {
+		interpreter.poll(index)
+		parts[index] = fmt.Sprint(item)
+	}
+	return strings.Join(parts, fmt.Sprint(args[0]))
+}
+
+func arrayIndexOf(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	for index, item := range this.([]interface{}) {
+		interpreter.poll(index)
+		if interpreter.isEqual(item, args[0]) {
+			return float64(index)
+		}
+	}
+	return float64(-1)
+}
+
+func arrayContains(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	return arrayIndexOf(interpreter, this, args).(float64) >= 0
+}
+
+func arrayReverse(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	array := this.([]interface{})
+	reversed := make([]interface{}, len(array))
+	for index, item := range array {
+		interpreter.poll(index)
+		reversed[len(array)-1-index] = item
+	}
+	return NewLoxArray(reversed)
+}
+
+func arraySlice(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	array := this.([]interface{})
+	start, end := int(args[0].(float64)), int(args[1].(float64))
+	if start < 0 {
+		start = len(array) + start
+	}
+	if end < 0 {
+		end = len(array) + end
+	}
+	start = int(math.Max(0, math.Min(float64(start), float64(len(array)))))
+	end = int(math.Max(float64(start), math.Min(float64(end), float64(len(array)))))
+	slice := make([]interface{}, end-start)
+	copy(slice, array[start:end])
+	return NewLoxArray(slice)
+}
+
+func arrayMap(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	fn := args[0].(LoxCallable)
+	array := this.([]interface{})
+	result := make([]interface{}, len(array))
+	for index, item := range array {
+		result[index] = interpreter.invoke(fn, item, float64(index))
+	}
+	return NewLoxArray(result)
+}
+
+func arrayFilter(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	fn := args[0].(LoxCallable)
+	result := []interface{}{}
+	for index, item := range this.([]interface{}) {
+		if interpreter.isTruthy(interpreter.invoke(fn, item, float64(index))) {
+			result = append(result, item)
+		}
+	}
+	return NewLoxArray(result)
+}
+
+func arrayReduce(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	fn := args[0].(LoxCallable)
+	accumulator := args[1]
+	for index, item := range this.([]interface{}) {
+		accumulator = interpreter.invoke(fn, accumulator, item, float64(index))
+	}
+	return accumulator
+}
+
+func arraySort(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	array := this.([]interface{})
+	sorted := make([]interface{}, len(array))
+	copy(sorted, array)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		left, leftIsNumber := sorted[a].(float64)
+		right, rightIsNumber := sorted[b].(float64)
+		if leftIsNumber && rightIsNumber {
+			return left < right
+		}
+		return fmt.Sprint(sorted[a]) < fmt.Sprint(sorted[b])
+	})
+	return NewLoxArray(sorted)
+}
+
+func mapLen(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	return float64(len(this.(map[interface{}]interface{})))
+}
+
+func mapKeys(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	return NewLoxArray(sortedKeys(this.(map[interface{}]interface{})))
+}
+
+func mapValues(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	m := this.(map[interface{}]interface{})
+	values := []interface{}{}
+	for _, key := range sortedKeys(m) {
+		values = append(values, m[key])
+	}
+	return NewLoxArray(values)
+}
+
+func mapHas(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	if !hashable(args[0]) {
+		return false
+	}
+	_, ok := this.(map[interface{}]interface{})[args[0]]
+	return ok
+}
+
+func mapRemove(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	m := this.(map[interface{}]interface{})
+	if !hashable(args[0]) {
+		return nil
+	}
+	value := m[args[0]]
+	delete(m, args[0])
+	return value
+}
+
+func toFloat(value interface{}) float64 {
+	switch number := value.(type) {
+	case int:
+		return float64(number)
+	case int64:
+		return float64(number)
+	}
+	return value.(float64)
+}
+
+func numberRound(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	number := toFloat(this)
+	if len(args) == 0 {
+		return math.Round(number)
+	}
+	scale := math.Pow(10, args[0].(float64))
+	return math.Round(number*scale) / scale
+}
+
+func numberFloor(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	return math.Floor(toFloat(this))
+}
+
+func numberCeil(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	return math.Ceil(toFloat(this))
+}
+
+func numberAbs(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	return math.Abs(toFloat(this))
+}
+
+func valueString(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	return fmt.Sprint(this)
+}
@@ -0,0 +1,96 @@
+package coati2lang
+
+var (
+	OPERATOR_METHODS = map[TokenType]string{
+		PLUS:          "__add",
+		MINUS:         "__sub",
+		STAR:          "__mul",
+		SLASH:         "__div",
+		STAR_STAR:     "__pow",
+		EQUAL_EQUAL:   "__eq",
+		LESS:          "__lt",
+		LESS_EQUAL:    "__le",
+		GREATER:       "__gt",
+		GREATER_EQUAL: "__ge",
+	}
+)
+
+// operatorMethod busca un metodo especial (__add, __eq, ...) en un objeto.
+func (i *Interpreter) operatorMethod(value interface{}, name string) (LoxCallable, bool) {
+	switch object := thaw(value).(type) {
+	case map[interface{}]interface{}:
+		method, ok := object[name].(LoxCallable)
+		return method, ok
+	case *LoxInstance:
+		if method, ok := object.Class.bind(name, object); ok {
+			return method, true
+		}
+	}
+	return nil, false
+}
+
+func (i *Interpreter) callOperator(method LoxCallable, name string, this interface{}, arguments ...interface{}) interface{} {
+	if method.Arity() != -1 && method.Arity() != len(arguments) {
+		raise(Token{}, ARGUMENT_ERROR, "Operator method '%s' expects %d arguments but has %d.", name, len(arguments), method.Arity())
+	}
+	frame := typeName(this) + "." + name
+	if instance, ok := this.(*LoxInstance); ok {
+		frame = instance.Class.Name + "." + name
+	}
+	return i.callFrame(frame, Token{}, func() interface{} {
+		return method.Call(i, arguments, this)
+	})
+}
+
+func (i *Interpreter) overloadBinary(operator Token, left interface{}, right interface{}) (interface{}, bool) {
+	switch operator.Type {
+	case BANG_EQUAL:
+		if result, ok := i.overloadBinary(Token{Type: EQUAL_EQUAL, Lexeme: "==", Line: operator.Line}, left, right); ok {
+			return !i.isTruthy(result), true
+		}
+		return nil, false
+	case EQUAL_EQUAL:
+		if method, ok := i.operatorMethod(left, "__eq"); ok {
+			return i.callOperator(method, "__eq", left, right), true
+		}
+		if method, ok := i.operatorMethod(right, "__eq"); ok {
+			return i.callOperator(method, "__eq", right, left), true
+		}
+		return nil, false
+	}
+
+	name, ok := OPERATOR_METHODS[operator.Type]
+	if !ok {
+		return nil, false
+	}
+	if method, ok := i.operatorMethod(left, name); ok {
+		return i.callOperator(method, name, left, right), true
+	}
+
+	// Las comparaciones que falten se derivan de __lt.
+	switch operator.Type {
+	case GREATER:
+		if method, ok := i.operatorMethod(right, "__lt"); ok {
+			return i.callOperator(method, "__lt", right, left), true
+		}
+	case LESS_EQUAL:
+		if method, ok := i.operatorMethod(right, "__lt"); ok {
+			return !i.isTruthy(i.callOperator(method, "__lt", right, left)), true
+		}
+	case GREATER_EQUAL:
+		if method, ok := i.operatorMethod(left, "__lt"); ok {
+			return !i.isTruthy(i.callOperator(method, "__lt", left, right)), true
+		}
+	}
+	return nil, false
+}
+
+func (i *Interpreter) overloadUnary(operator Token, value interface{}) (interface{}, bool) {
+	if operator.Type != MINUS {
+		return nil, false
+	}
+	if method, ok := i.operatorMethod(value, "__neg"); ok {
+		return i.callOperator(method, "__neg", value), true
+	}
+	return nil, false
+}
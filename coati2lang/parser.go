@@ -2,15 +2,28 @@ package coati2lang
 
 import (
 	"fmt"
-	"os"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
 
+var COMPOUND_OPERATORS = map[TokenType]TokenType{
+	PLUS_EQUAL:      PLUS,
+	MINUS_EQUAL:     MINUS,
+	STAR_EQUAL:      STAR,
+	SLASH_EQUAL:     SLASH,
+	STAR_STAR_EQUAL: STAR_STAR,
+}
+
 type Parser struct {
-	Tokens  []Token
-	Current int
-	Start   int
+	Tokens     []Token
+	Current    int
+	Start      int
+	generators []bool
+	Errors     []error
+
+	// Strict acepta la sintaxis de Lox del libro (sentencia print).
+	Strict bool
 }
 
 func NewParser(tokens []Token) *Parser {
@@ -37,13 +50,65 @@ func (p *Parser) ReturnStatement() Stmt {
 	keyword := p.previous()
 	var value Expr
 	if !p.check(SEMICOLON) {
-		value = p.Expression()
+		value = p.ExpressionList()
 	}
 
 	p.consume(SEMICOLON, "Expect ';' after return value.")
 	return Return{Keyword: keyword, Value: value}
 }
 
+func (p *Parser) YieldStatement() Stmt {
+	keyword := p.previous()
+	if len(p.generators) > 0 {
+		p.generators[len(p.generators)-1] = true
+	}
+	var value Expr
+	if !p.check(SEMICOLON) {
+		value = p.Expression()
+	}
+
+	p.consume(SEMICOLON, "Expect ';' after yield value.")
+	return Yield{Keyword: keyword, Value: value}
+}
+
+func (p *Parser) TryStatement() Stmt {
+	stmt := Try{Keyword: p.previous()}
+	p.consume(LEFT_BRACE, "Expect '{' after 'try'.")
+	stmt.Body = p.Block()
+
+	if p.match(CATCH) {
+		if p.match(LEFT_PAREN) {
+			name := p.consume(IDENTIFIER, "Expect error name after '('.")
+			stmt.CatchName = &name
+			p.consume(RIGHT_PAREN, "Expect ')' after error name.")
+		}
+		p.consume(LEFT_BRACE, "Expect '{' after catch.")
+		stmt.Catch = p.Block()
+	}
+	if p.match(FINALLY) {
+		p.consume(LEFT_BRACE, "Expect '{' after 'finally'.")
+		stmt.Finally = p.Block()
+	}
+	if stmt.Catch == nil && stmt.Finally == nil {
+		p.error(stmt.Keyword, "Expect 'catch' or 'finally' after try block.")
+	}
+	return stmt
+}
+
+func (p *Parser) ThrowStatement() Stmt {
+	keyword := p.previous()
+	value := p.Expression()
+	p.consume(SEMICOLON, "Expect ';' after throw value.")
+	return Throw{Keyword: keyword, Value: value}
+}
+
+func (p *Parser) PrintStatement() Stmt {
+	keyword := p.advance()
+	value := p.Expression()
+	p.consume(SEMICOLON, "Expect ';' after value.")
+	return PrintStmt{Keyword: keyword, Expression: value}
+}
+
 func (p *Parser) Comparison() Expr {
 	expr := p.Term()
 
@@ -65,47 +130,6 @@ func (p *Parser) Term() Expr {
 		expr = Binary{Left: expr, Operator: operator, Right: right}
 	}
 
-	for p.match(MINUS_MINUS) {
-		op := p.previous()
-		operator := Token{Type: MINUS, Lexeme: "--", Literal: nil, Line: op.Line}
-		right := Literal{Value: 1.0}
-		var name string
-		if expr == nil {
-			expr = p.Factor()
-		}
-		if expr, ok := expr.(Var); ok {
-			name = expr.Name.Lexeme
-		}
-
-		expr = Assign{Name: Token{
-			Type:    IDENTIFIER,
-			Lexeme:  name,
-			Literal: nil,
-			Line:    op.Line,
-		}, Value: Binary{Left: expr, Operator: operator, Right: right}}
-
-	}
-
-	for p.match(PLUS_PLUS) {
-		op := p.previous()
-		operator := Token{Type: PLUS, Lexeme: "++", Literal: nil, Line: op.Line}
-		right := Literal{Value: 1.0}
-		var name string
-		if expr == nil {
-			expr = p.Factor()
-		}
-		if expr, ok := expr.(Var); ok {
-			name = expr.Name.Lexeme
-		}
-
-		expr = Assign{Name: Token{
-			Type:    IDENTIFIER,
-			Lexeme:  name,
-			Literal: nil,
-			Line:    op.Line,
-		}, Value: Binary{Left: expr, Operator: operator, Right: right}}
-	}
-
 	return expr
 }
 
@@ -122,13 +146,35 @@ func (p *Parser) Factor() Expr {
 }
 
 func (p *Parser) Unary() Expr {
-	if p.match(BANG, MINUS) {
+	if p.match(BANG, MINUS, TYPEOF) {
 		operator := p.previous()
 		value := p.Unary()
 		return Unary{Operator: operator, Value: value}
 	}
 
-	return p.Call()
+	if p.match(PLUS_PLUS, MINUS_MINUS) {
+		operator := p.previous()
+		target := p.Unary()
+		p.checkTarget(target, operator)
+		return Increment{Target: target, Operator: operator, Prefix: true}
+	}
+
+	expr := p.Call()
+	if p.match(PLUS_PLUS, MINUS_MINUS) {
+		operator := p.previous()
+		p.checkTarget(expr, operator)
+		return Increment{Target: expr, Operator: operator}
+	}
+	return expr
+}
+
+// checkTarget valida que expr se pueda asignar (variable, propiedad o indice).
+func (p *Parser) checkTarget(expr Expr, operator Token) {
+	root, selectors, ok := flattenTarget(expr)
+	if _, isVar := root.(Var); ok && (isVar || len(selectors) > 0) {
+		return
+	}
+	p.error(operator, "Invalid assignment target.")
 }
 
 func (p *Parser) primary() Expr {
@@ -147,24 +193,33 @@ func (p *Parser) primary() Expr {
 	}
 
 	if p.match(IDENTIFIER) {
-		name := p.previous()
-
-		selectors := [][]Expr{}
-		for p.check(LEFT_BRACKET) || p.check(DOT) {
-			if p.match(LEFT_BRACKET) {
-				array := p.Array()
-				selectors = append(selectors, array)
-			} else if p.match(DOT) {
-				name := p.consume(IDENTIFIER, "Expect property name after '.'.")
-				selectors = append(selectors, []Expr{Literal{Value: name.Lexeme}})
-			}
-		}
+		return Var{Name: p.previous()}
+	}
 
-		return Var{Name: name, Selectors: selectors}
+	if p.match(SUPER) {
+		keyword := p.previous()
+		p.consume(DOT, "Expect '.' after 'super'.")
+		method := p.consume(IDENTIFIER, "Expect superclass method name.")
+		return Super{Keyword: keyword, Method: method}
 	}
 
 	if p.match(LEFT_PAREN) {
-		expr := p.Equality()
+		paren := p.previous()
+		if p.match(RIGHT_PAREN) {
+			return TupleLiteral{Paren: paren, Elements: []Expr{}}
+		}
+		expr := p.Expression()
+		if p.match(COMMA) {
+			elements := []Expr{expr}
+			for !p.check(RIGHT_PAREN) && !p.isAtEnd() {
+				elements = append(elements, p.Expression())
+				if !p.match(COMMA) {
+					break
+				}
+			}
+			p.consume(RIGHT_PAREN, "Expect ')' after tuple elements.")
+			return TupleLiteral{Paren: paren, Elements: elements}
+		}
 		p.consume(RIGHT_PAREN, "Expect ')' after expression.")
 		return Grouping{Expression: expr}
 	}
@@ -179,16 +234,131 @@ func (p *Parser) primary() Expr {
 		return Literal{Value: array}
 	}
 
+	if p.match(HASH_BRACE) {
+		brace := p.previous()
+		elements := []Expr{}
+		for !p.check(RIGHT_BRACE) && !p.isAtEnd() {
+			elements = append(elements, p.Expression())
+			if !p.match(COMMA) {
+				break
+			}
+		}
+		p.consume(RIGHT_BRACE, "Expect '}' after set elements.")
+		return SetLiteral{Brace: brace, Elements: elements}
+	}
+
+	if p.match(MATCH) {
+		return p.MatchExpression()
+	}
+
 	if p.match(PIPE) {
+		pipe := p.previous()
 		expr := p.Equality()
 		p.consume(PIPE, "Expect '|' after expression.")
-		return GroupingABS{Expression: expr}
+		return GroupingABS{Pipe: pipe, Expression: expr}
 	}
 
-	if p.match(EOF) {
-		return Literal{Value: nil}
+	p.fail("Expect expression.")
+	return nil
+}
+
+func (p *Parser) MatchExpression() Expr {
+	keyword := p.previous()
+	p.consume(LEFT_PAREN, "Expect '(' after 'match'.")
+	subject := p.Expression()
+	p.consume(RIGHT_PAREN, "Expect ')' after match value.")
+	p.consume(LEFT_BRACE, "Expect '{' before match arms.")
+
+	arms := []MatchArm{}
+	for !p.check(RIGHT_BRACE) && !p.isAtEnd() {
+		arm := MatchArm{Pattern: p.Pattern()}
+		if p.match(IF) {
+			arm.Guard = p.Expression()
+		}
+		p.consume(ARROW, "Expect '=>' after pattern.")
+		arm.Body = p.Expression()
+		arms = append(arms, arm)
+		if !p.match(COMMA, SEMICOLON) {
+			break
+		}
+	}
+
+	p.consume(RIGHT_BRACE, "Expect '}' after match arms.")
+	return Match{Keyword: keyword, Subject: subject, Arms: arms}
+}
+
+func (p *Parser) Pattern() Pattern {
+	if p.match(FALSE) {
+		return LiteralPattern{Value: false}
+	}
+	if p.match(TRUE) {
+		return LiteralPattern{Value: true}
+	}
+	if p.match(NIL) {
+		return LiteralPattern{Value: nil}
+	}
+	if p.match(NUMBER, STRING, MULTILINE_STRING, TEMPLATE_STRING) {
+		return LiteralPattern{Value: p.previous().Literal}
+	}
+	if p.match(MINUS) {
+		number := p.consume(NUMBER, "Expect number after '-' in pattern.")
+		return LiteralPattern{Value: -number.Literal.(float64)}
+	}
+
+	if p.check(IDENTIFIER) && p.checkNext(DOT) {
+		return ValuePattern{Value: p.Call()}
+	}
+	if p.match(IDENTIFIER) {
+		name := p.previous()
+		if name.Lexeme == "_" {
+			return WildcardPattern{}
+		}
+		return BindingPattern{Name: name}
+	}
+
+	if p.match(LEFT_BRACKET) {
+		pattern := ArrayPattern{}
+		for !p.check(RIGHT_BRACKET) && !p.isAtEnd() {
+			if p.match(DOT) {
+				p.consume(DOT, "Expect '...' before rest name.")
+				p.consume(DOT, "Expect '...' before rest name.")
+				rest := p.consume(IDENTIFIER, "Expect name after '...'.")
+				pattern.Rest = &rest
+				break
+			}
+			pattern.Elements = append(pattern.Elements, p.Pattern())
+			if !p.match(COMMA) {
+				break
+			}
+		}
+		p.consume(RIGHT_BRACKET, "Expect ']' after array pattern.")
+		return pattern
+	}
+
+	if p.match(LEFT_BRACE) {
+		pattern := MapPattern{}
+		for !p.check(RIGHT_BRACE) && !p.isAtEnd() {
+			var key Token
+			if p.match(STRING) {
+				key = p.previous()
+				key.Lexeme = key.Literal.(string)
+			} else {
+				key = p.consume(IDENTIFIER, "Expect key in map pattern.")
+			}
+			entry := MapPatternEntry{Key: key.Lexeme, Pattern: BindingPattern{Name: key}}
+			if p.match(COLON) {
+				entry.Pattern = p.Pattern()
+			}
+			pattern.Entries = append(pattern.Entries, entry)
+			if !p.match(COMMA) {
+				break
+			}
+		}
+		p.consume(RIGHT_BRACE, "Expect '}' after map pattern.")
+		return pattern
 	}
 
+	p.fail("Expect pattern.")
 	return nil
 }
 
@@ -218,14 +388,61 @@ func (p *Parser) previous() Token {
 	return p.Tokens[p.Current-1]
 }
 
+func (p *Parser) checkNext(t TokenType) bool {
+	return p.checkAt(1, t)
+}
+
+func (p *Parser) checkAt(offset int, t TokenType) bool {
+	if p.Current+offset >= len(p.Tokens) {
+		return false
+	}
+	return p.Tokens[p.Current+offset].Type == t
+}
+
 func (p *Parser) consume(t TokenType, message string) Token {
-	line := p.peek().Line
 	if p.check(t) {
 		return p.advance()
 	}
 
-	message1 := fmt.Sprint(message, "\n\tLine -> ", fmt.Sprint(line), "\n\tNear ->"+p.peek().Lexeme)
-	panic(message1)
+	p.fail(message)
+	return Token{}
+}
+
+// propertyName acepta tambien palabras reservadas como nombre de propiedad
+// o metodo (set.add, obj.delete).
+func (p *Parser) propertyName(message string) Token {
+	if p.isAtEnd() {
+		return p.consume(IDENTIFIER, message)
+	}
+	if _, ok := keywords[p.peek().Lexeme]; ok {
+		name := p.advance()
+		name.Type = IDENTIFIER
+		return name
+	}
+	return p.consume(IDENTIFIER, message)
+}
+
+// fail reporta el error en el token actual y corta la declaracion; la
+// recupera Declaration sincronizando en el proximo limite de sentencia.
+func (p *Parser) fail(message string) {
+	token := p.Tokens[len(p.Tokens)-1]
+	if !p.isAtEnd() {
+		token = p.peek()
+	}
+	panic(p.syntaxError(token, message))
+}
+
+// error reporta el error sin cortar el parseo.
+func (p *Parser) error(token Token, message string) {
+	p.Errors = append(p.Errors, p.syntaxError(token, message))
+}
+
+func (p *Parser) syntaxError(token Token, message string) SyntaxError {
+	where := " at '" + token.Lexeme + "'"
+	if token.Type == EOF {
+		where = " at end"
+	}
+	return SyntaxError{Line: token.Line, Column: token.Column, Length: utf8.RuneCountInString(token.Lexeme), Where: where, Message: message}
 }
 
 func (p *Parser) assignment() Expr {
@@ -235,13 +452,26 @@ func (p *Parser) assignment() Expr {
 		equals := p.previous()
 		value := p.assignment()
 
-		if expr, ok := expr.(Var); ok {
-			name := expr.Name
-
-			return Assign{Name: name, Value: value, Selectors: expr.Selectors}
+		if root, selectors, ok := flattenTarget(expr); ok {
+			if variable, ok := root.(Var); ok {
+				return Assign{Name: variable.Name, Value: value, Selectors: selectors}
+			}
+			if len(selectors) > 0 {
+				return Set{Object: root, Equals: equals, Selectors: selectors, Value: value}
+			}
 		}
 
-		Errors(equals.Line, "Invalid assignment target.")
+		p.error(equals, "Invalid assignment target.")
+	}
+
+	if p.match(PLUS_EQUAL, MINUS_EQUAL, STAR_EQUAL, SLASH_EQUAL, STAR_STAR_EQUAL) {
+		operator := p.previous()
+		value := p.assignment()
+		p.checkTarget(expr, operator)
+
+		operator.Type = COMPOUND_OPERATORS[operator.Type]
+		operator.Lexeme = operator.Lexeme[:len(operator.Lexeme)-1]
+		return CompoundAssign{Target: expr, Operator: operator, Value: value}
 	}
 
 	return expr
@@ -280,13 +510,7 @@ func (p *Parser) synchronize() {
 		}
 
 		switch p.peek().Type {
-		case CLASS:
-		case FUN:
-		case VAR:
-		case FOR:
-		case IF:
-		case WHILE:
-		case RETURN:
+		case CLASS, FUN, VAR, LET, ENUM, EXTEND, FOR, IF, WHILE, RETURN, YIELD, TRY, THROW:
 			return
 		}
 
@@ -294,12 +518,16 @@ func (p *Parser) synchronize() {
 	}
 }
 
-func (p *Parser) Declaration() Stmt {
+func (p *Parser) Declaration() (stmt Stmt) {
 	defer func() {
 		if r := recover(); r != nil {
-			fmt.Fprintf(os.Stderr, "%s", r)
-			os.Exit(2)
-			//p.synchronize()
+			err, ok := r.(SyntaxError)
+			if !ok {
+				panic(r)
+			}
+			p.Errors = append(p.Errors, err)
+			p.synchronize()
+			stmt = nil
 		}
 	}()
 
@@ -308,6 +536,10 @@ func (p *Parser) Declaration() Stmt {
 		return p.Function("function", name)
 	}
 
+	if p.match(CLASS) {
+		return p.ClassDeclaration()
+	}
+
 	if p.match(VAR) {
 		return p.VarDeclaration()
 	}
@@ -316,6 +548,14 @@ func (p *Parser) Declaration() Stmt {
 		return p.VarDeclaration()
 	}
 
+	if p.match(ENUM) {
+		return p.EnumDeclaration()
+	}
+
+	if p.match(EXTEND) {
+		return p.ExtendDeclaration()
+	}
+
 	if p.match(EOF) {
 		return nil
 	}
@@ -330,7 +570,7 @@ func (p *Parser) Function(kind string, name Token) Stmt {
 	if !p.check(RIGHT_PAREN) {
 		for {
 			if len(parameters) >= 255 {
-				Errors(p.peek().Line, "Can't have more than 255 parameters.")
+				p.error(p.peek(), "Can't have more than 255 parameters.")
 			}
 			parameters = append(parameters, p.consume(IDENTIFIER, "Expect parameter name."))
 			if !p.match(COMMA) {
@@ -342,16 +582,35 @@ func (p *Parser) Function(kind string, name Token) Stmt {
 
 	p.consume(LEFT_BRACE, "Expect '{' before "+kind+" body.")
 
+	p.generators = append(p.generators, false)
 	body := p.Block()
-	return Function{Name: name, Parameters: parameters, Body: body, Closure: nil}
+	isGenerator := p.generators[len(p.generators)-1]
+	p.generators = p.generators[:len(p.generators)-1]
+	return Function{Name: name, Parameters: parameters, Body: body, Closure: nil, IsGenerator: isGenerator}
 }
 
 func (p *Parser) VarDeclaration() Stmt {
 	name := p.consume(IDENTIFIER, "Expect variable name.")
 
+	if p.match(COMMA) {
+		names := []Token{name}
+		for {
+			names = append(names, p.consume(IDENTIFIER, "Expect variable name."))
+			if !p.match(COMMA) {
+				break
+			}
+		}
+		p.consume(EQUAL, "Expect '=' after variable names.")
+		equals := p.previous()
+		value := p.ExpressionList()
+		p.checkValues(value, len(names), equals)
+		p.consume(SEMICOLON, "Expect ';' after variable declaration.")
+		return MultiVar{Names: names, Value: value}
+	}
+
 	if p.match(EQUAL) {
 		initializer := p.Expression()
-		p.consume(SEMICOLON, "Expect ';' after variable declaration.(0)")
+		p.consume(SEMICOLON, "Expect ';' after variable declaration.")
 		return Var{Name: name, InitializerVal: initializer}
 	}
 	if p.match(LEFT_BRACKET) {
@@ -366,10 +625,10 @@ func (p *Parser) VarDeclaration() Stmt {
 				inizializer = p.Array()
 
 				if size_declarate != len(inizializer) {
-					Errors(size.Line, "Size of array and number of arguments must be the same.")
+					p.error(size, "Size of array and number of arguments must be the same.")
 				}
 			}
-			p.consume(SEMICOLON, "Expect ';' after variable declaration.(1)")
+			p.consume(SEMICOLON, "Expect ';' after variable declaration.")
 
 			return Var{Name: name, InitializerArray: inizializer, SizeArrayInit: size_declarate}
 		}
@@ -379,11 +638,11 @@ func (p *Parser) VarDeclaration() Stmt {
 			p.consume(EQUAL, "Expect '=' after ']'.")
 			p.consume(LEFT_BRACKET, "Expect '[' after '='.")
 			initializer := p.Array()
-			p.consume(SEMICOLON, "Expect ';' after variable declaration.(2)")
+			p.consume(SEMICOLON, "Expect ';' after variable declaration.")
 			return Var{Name: name, InitializerArray: initializer, SizeArrayInit: len(initializer)}
 		}
 
-		p.consume(SEMICOLON, "Expect ';' after variable declaration.(3)")
+		p.consume(SEMICOLON, "Expect ';' after variable declaration.")
 		return Var{Name: name, InitializerArray: inizializer, SizeArrayInit: 0}
 	}
 
@@ -392,21 +651,81 @@ func (p *Parser) VarDeclaration() Stmt {
 		p.consume(EQUAL, "Expect '=' after '}'.")
 		p.consume(LEFT_BRACE, "Expect '{' after '='.")
 		initializer := p.Map()
-		p.consume(SEMICOLON, "Expect ';' after variable declaration.(4)")
+		p.consume(SEMICOLON, "Expect ';' after variable declaration.")
 		return Var{Name: name, InitializerMap: initializer}
 	}
 
-	p.consume(SEMICOLON, "Expect ';' after variable declaration.(5)")
+	p.consume(SEMICOLON, "Expect ';' after variable declaration.")
 	return Var{Name: name, InitializerVal: nil}
 }
 
+func (p *Parser) ClassDeclaration() Stmt {
+	name := p.consume(IDENTIFIER, "Expect class name.")
+
+	var superclass *Var
+	if p.match(LESS) {
+		p.consume(IDENTIFIER, "Expect superclass name.")
+		superclass = &Var{Name: p.previous()}
+	}
+
+	p.consume(LEFT_BRACE, "Expect '{' before class body.")
+
+	methods := []Function{}
+	for !p.check(RIGHT_BRACE) && !p.isAtEnd() {
+		name := p.propertyName("Expect method name.")
+		methods = append(methods, p.Function("method", name).(Function))
+	}
+
+	p.consume(RIGHT_BRACE, "Expect '}' after class body.")
+	return Class{Name: name, Superclass: superclass, Methods: methods}
+}
+
+func (p *Parser) EnumDeclaration() Stmt {
+	name := p.consume(IDENTIFIER, "Expect enum name.")
+	p.consume(LEFT_BRACE, "Expect '{' before enum body.")
+
+	members := []EnumItem{}
+	for !p.check(RIGHT_BRACE) && !p.isAtEnd() {
+		member := EnumItem{Name: p.consume(IDENTIFIER, "Expect enum member name.")}
+		if p.match(EQUAL) {
+			member.Value = p.Expression()
+		}
+		members = append(members, member)
+		if !p.match(COMMA) {
+			break
+		}
+	}
+
+	p.consume(RIGHT_BRACE, "Expect '}' after enum body.")
+	p.match(SEMICOLON)
+	return Enum{Name: name, Members: members}
+}
+
+func (p *Parser) ExtendDeclaration() Stmt {
+	keyword := p.previous()
+	typeName := p.consume(IDENTIFIER, "Expect type name after 'extend'.")
+	p.consume(LEFT_BRACE, "Expect '{' before extend body.")
+
+	methods := []Function{}
+	for !p.check(RIGHT_BRACE) && !p.isAtEnd() {
+		name := p.propertyName("Expect method name.")
+		methods = append(methods, p.Function("method", name).(Function))
+	}
+
+	p.consume(RIGHT_BRACE, "Expect '}' after extend body.")
+	return Extend{Keyword: keyword, Type: typeName, Methods: methods}
+}
+
 func (p *Parser) Array() []Expr {
 	initializer := []Expr{}
 
 	if !p.check(RIGHT_BRACKET) {
 		for {
+			if p.check(RIGHT_BRACKET) {
+				break
+			}
 			if len(initializer) >= 255 {
-				Errors(p.peek().Line, "Can't have more than 255 arguments.")
+				p.error(p.peek(), "Can't have more than 255 arguments.")
 			}
 			expr := p.Expression()
 
@@ -420,7 +739,7 @@ func (p *Parser) Array() []Expr {
 				}
 			} else if p.match(LEFT_BRACKET) {
 				subarray := p.Array()
-				subVar := Var{Name: Token{Type: IDENTIFIER, Lexeme: "subarray-" + uuid.NewString()}, Sub: true, InitializerArray: subarray}
+				subVar := Var{Name: Token{Type: IDENTIFIER, Lexeme: "subarray-" + uuid.NewString()}, Sub: true, InitializerArray: subarray, SizeArrayInit: len(subarray)}
 				initializer = append(initializer, subVar)
 			} else if p.match(LEFT_BRACE) {
 				submap := p.Map()
@@ -451,9 +770,12 @@ func (p *Parser) Map() []ItemVar {
 
 	if !p.check(RIGHT_BRACE) {
 		for {
-
+			// Se permite ; o , despues del ultimo elemento.
+			if p.check(RIGHT_BRACE) {
+				break
+			}
 			if len(initializer) >= 255 {
-				Errors(p.peek().Line, "Can't have more than 255 arguments.")
+				p.error(p.peek(), "Can't have more than 255 arguments.")
 			}
 			key := p.Expression()
 
@@ -477,7 +799,7 @@ func (p *Parser) Map() []ItemVar {
 
 				if p.match(LEFT_BRACKET) {
 					subarray := p.Array()
-					subVar := Var{Name: Token{Type: IDENTIFIER, Lexeme: "subarray-" + uuid.NewString()}, Sub: true, InitializerArray: subarray}
+					subVar := Var{Name: Token{Type: IDENTIFIER, Lexeme: "subarray-" + uuid.NewString()}, Sub: true, InitializerArray: subarray, SizeArrayInit: len(subarray)}
 					initializer = append(initializer, ItemVar{Key: key, Value: subVar})
 				} else if p.match(LEFT_BRACE) {
 					submap := p.Map()
@@ -528,6 +850,22 @@ func (p *Parser) Statement() Stmt {
 		return p.ReturnStatement()
 	}
 
+	if p.match(YIELD) {
+		return p.YieldStatement()
+	}
+
+	if p.match(TRY) {
+		return p.TryStatement()
+	}
+
+	if p.match(THROW) {
+		return p.ThrowStatement()
+	}
+
+	if p.Strict && p.check(IDENTIFIER) && p.peek().Lexeme == "print" {
+		return p.PrintStatement()
+	}
+
 	if p.match(WHILE) {
 		return p.WhileStatement()
 	}
@@ -551,6 +889,7 @@ func (p *Parser) Block() []Stmt {
 }
 
 func (p *Parser) IfStatement() Stmt {
+	keyword := p.previous()
 	p.consume(LEFT_PAREN, "Expect '(' after 'if'.")
 	condition := p.Expression()
 	p.consume(RIGHT_PAREN, "Expect ')' after if condition.")
@@ -561,19 +900,24 @@ func (p *Parser) IfStatement() Stmt {
 		elseBranch = p.Statement()
 	}
 
-	return If{Condition: condition, ThenBranch: thenBranch, ElseBranch: elseBranch}
+	return If{Keyword: keyword, Condition: condition, ThenBranch: thenBranch, ElseBranch: elseBranch}
 }
 
 func (p *Parser) WhileStatement() Stmt {
+	keyword := p.previous()
 	p.consume(LEFT_PAREN, "Expect '(' after 'while'.")
 	condition := p.Expression()
 	p.consume(RIGHT_PAREN, "Expect ')' after while condition.")
 	body := p.Statement()
-	return While{Condition: condition, Body: body}
+	return While{Keyword: keyword, Condition: condition, Body: body}
 }
 
 func (p *Parser) ForStatement() Stmt {
+	keyword := p.previous()
 	p.consume(LEFT_PAREN, "Expect '(' after 'for'.")
+	if p.check(IDENTIFIER) && p.checkNext(IN) || p.check(VAR) && p.checkAt(2, IN) {
+		return p.ForInStatement()
+	}
 	var initializer Stmt
 	if p.match(SEMICOLON) {
 		initializer = nil
@@ -604,7 +948,7 @@ func (p *Parser) ForStatement() Stmt {
 	if condition == nil {
 		condition = Literal{Value: true}
 	}
-	body = While{Condition: condition, Body: body}
+	body = While{Keyword: keyword, Condition: condition, Body: body}
 
 	if initializer != nil {
 		body = Block{Statements: []Stmt{initializer, body}}
@@ -613,12 +957,40 @@ func (p *Parser) ForStatement() Stmt {
 	return body
 }
 
+func (p *Parser) ForInStatement() Stmt {
+	p.match(VAR)
+	name := p.consume(IDENTIFIER, "Expect variable name.")
+	p.consume(IN, "Expect 'in' after variable name.")
+	iterable := p.Expression()
+	p.consume(RIGHT_PAREN, "Expect ')' after for clauses.")
+	body := p.Statement()
+	return ForIn{Name: name, Iterable: iterable, Body: body}
+}
+
 func (p *Parser) Call() Expr {
 	expr := p.primary()
 
+	// Una vez que aparece ?. el resto de la cadena tambien corta en nil.
+	optional := false
 	for {
 		if p.match(LEFT_PAREN) {
-			expr = p.finishCall(expr)
+			expr = p.finishCall(expr, optional)
+		} else if p.match(DOT) {
+			name := p.propertyName("Expect property name after '.'.")
+			expr = Get{Object: expr, Name: name, Optional: optional}
+		} else if p.match(LEFT_BRACKET) {
+			expr = p.finishIndex(expr, optional)
+		} else if p.match(QUESTION_DOT) {
+			optional = true
+			if p.match(LEFT_PAREN) {
+				expr = p.finishCall(expr, optional)
+			} else {
+				name := p.propertyName("Expect property name after '?.'.")
+				expr = Get{Object: expr, Name: name, Optional: optional}
+			}
+		} else if p.match(QUESTION_BRACKET) {
+			optional = true
+			expr = p.finishIndex(expr, optional)
 		} else {
 			break
 		}
@@ -627,21 +999,19 @@ func (p *Parser) Call() Expr {
 	return expr
 }
 
-func (p *Parser) finishCall(callee Expr) Expr {
-	arguments := []Expr{}
+func (p *Parser) finishIndex(object Expr, optional bool) Expr {
+	bracket := p.previous()
+	indexes := p.Array()
+	return Index{Object: object, Bracket: bracket, Indexes: indexes, Optional: optional}
+}
 
-	var this Var
-	if self, ok := callee.(Var); ok {
-		if len(self.Selectors) > 0 {
-			this = self
-			this.Selectors = this.Selectors[:len(this.Selectors)-1]
-		}
-	}
+func (p *Parser) finishCall(callee Expr, optional bool) Expr {
+	arguments := []Expr{}
 
 	if !p.check(RIGHT_PAREN) {
 		for {
 			if len(arguments) >= 255 {
-				Errors(p.peek().Line, "Can't have more than 255 arguments.")
+				p.error(p.peek(), "Can't have more than 255 arguments.")
 			}
 			arguments = append(arguments, p.Expression())
 			if !p.match(COMMA) {
@@ -650,26 +1020,18 @@ func (p *Parser) finishCall(callee Expr) Expr {
 		}
 	}
 
-	var call *Call
-	if p.check(DOT) {
-		p.consume(DOT, "Expect '.' after arguments.")
-		literal := p.Expression()
-		if call1, ok := literal.(Call); ok {
-			call = &call1
-		} else {
-			Errors(p.peek().Line, "Invalid call.")
-		}
-	}
-
 	paren := p.consume(RIGHT_PAREN, "Expect ')' after arguments.")
 
-	return Call{Callee: callee, Paren: paren, Arguments: arguments, This: this, SubCall: call}
+	return Call{Callee: callee, Paren: paren, Arguments: arguments, Optional: optional}
 }
 
 func (p *Parser) ExpressionStatement() Stmt {
 	var stmt Stmt
 
 	expr := p.Expression()
+	if p.check(COMMA) {
+		expr = p.MultiAssignment(expr)
+	}
 	p.consume(SEMICOLON, "Expect ';' after expression.")
 	stmt = Expression{Expression: expr}
 
@@ -680,19 +1042,53 @@ func (p *Parser) Expression() Expr {
 	return p.assignment()
 }
 
-func (p *Parser) Parse() []Stmt {
-	defer func() {
-		if r := recover(); r != nil {
-			p.synchronize()
-		}
-	}()
+// ExpressionList lee a, b, c. Con mas de un valor devuelve una tupla
+// (return a, b; var x, y = 1, 2;).
+func (p *Parser) ExpressionList() Expr {
+	paren := p.peek()
+	expr := p.Expression()
+	if !p.check(COMMA) {
+		return expr
+	}
+	values := []Expr{expr}
+	for p.match(COMMA) {
+		values = append(values, p.Expression())
+	}
+	return TupleLiteral{Paren: paren, Elements: values}
+}
+
+func (p *Parser) MultiAssignment(first Expr) Expr {
+	targets := []Expr{first}
+	for p.match(COMMA) {
+		targets = append(targets, p.or())
+	}
+	equals := p.consume(EQUAL, "Expect '=' after assignment targets.")
+	for _, target := range targets {
+		p.checkTarget(target, equals)
+	}
+	value := p.ExpressionList()
+	p.checkValues(value, len(targets), equals)
+	return MultiAssign{Targets: targets, Equals: equals, Value: value}
+}
+
+// checkValues avisa cuando una lista de valores no coincide con los nombres.
+func (p *Parser) checkValues(value Expr, count int, equals Token) {
+	if tuple, ok := value.(TupleLiteral); ok && len(tuple.Elements) != count {
+		p.error(equals, fmt.Sprintf("Expected %d values but got %d.", count, len(tuple.Elements)))
+	}
+}
+
+// Parse devuelve las sentencias y todos los errores de sintaxis del
+// archivo; si hay errores las sentencias no se deben ejecutar.
+func (p *Parser) Parse() ([]Stmt, []error) {
 	statements := []Stmt{}
 	for !p.isAtEnd() {
-		statements = append(statements, p.Declaration())
+		if stmt := p.Declaration(); stmt != nil {
+			statements = append(statements, stmt)
+		}
 	}
 
-	return statements
-
+	return statements, p.Errors
 }
 
 func (p *Parser) isAtEnd() bool {
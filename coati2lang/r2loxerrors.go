@@ -2,19 +2,109 @@ package coati2lang
 
 import (
 	"fmt"
+	"runtime"
 )
 
-func Errors(line int, message string) string {
+// SyntaxError es un error del scanner o del parser. Where indica el token
+// (" at 'x'" o " at end") y queda vacio en los errores del scanner.
+type SyntaxError struct {
+	Line    int
+	Column  int
+	Length  int
+	Where   string
+	Message string
+}
+
+func (e SyntaxError) Error() string {
+	return fmt.Sprintf("[line %d] Error%s: %s", e.Line, e.Where, e.Message)
+}
+
+type ErrorKind string
+
+const (
+	RUNTIME_ERROR    ErrorKind = "RuntimeError"
+	TYPE_ERROR       ErrorKind = "TypeError"
+	NAME_ERROR       ErrorKind = "NameError"
+	INDEX_ERROR      ErrorKind = "IndexError"
+	ARGUMENT_ERROR   ErrorKind = "ArgumentError"
+	VALUE_ERROR      ErrorKind = "ValueError"
+	MATCH_ERROR      ErrorKind = "MatchError"
+	INTERNAL_ERROR   ErrorKind = "InternalError"
+	THROWN_ERROR     ErrorKind = "Error"
+	STACK_OVERFLOW   ErrorKind = "StackOverflowError"
+	PERMISSION_ERROR ErrorKind = "PermissionError"
+	IO_ERROR         ErrorKind = "IOError"
+
+	STEP_LIMIT_ERROR   ErrorKind = "StepLimitError"
+	MEMORY_LIMIT_ERROR ErrorKind = "MemoryLimitError"
+	CANCELLED_ERROR    ErrorKind = "CancelledError"
+)
+
+// RuntimeError es el unico error que levantan los visitors durante la
+// ejecucion; Interpret lo devuelve como error de Go. Value es lo que se
+// paso a throw y Stack la pila de Lox en el momento del error.
+type RuntimeError struct {
+	Token   Token
+	Kind    ErrorKind
+	Message string
+	Value   interface{}
+	Stack   []Frame
+	Hint    string
+	// Cause es el error de Go detras de este, si hay (ErrStepLimit,
+	// context.DeadlineExceeded...); sirve para errors.Is.
+	Cause error
+}
+
+func (e *RuntimeError) Unwrap() error {
+	return e.Cause
+}
 
-	return Report(line, "", message)
+func (e *RuntimeError) Error() string {
+	message := e.Message
+	if e.Hint != "" {
+		message += " " + e.Hint
+	}
+	if e.Token.Line == 0 {
+		return fmt.Sprintf("%s: %s", e.Kind, message)
+	}
+	return fmt.Sprintf("[line %d] %s: %s", e.Token.Line, e.Kind, message)
+}
+
+func raise(token Token, kind ErrorKind, format string, args ...interface{}) {
+	panic(&RuntimeError{Token: token, Kind: kind, Message: fmt.Sprintf(format, args...)})
+}
+
+// raiseHint es raise con una sugerencia, ej. "Did you mean 'upper'?".
+func raiseHint(token Token, kind ErrorKind, hint string, format string, args ...interface{}) {
+	panic(&RuntimeError{Token: token, Kind: kind, Message: fmt.Sprintf(format, args...), Hint: hint})
 }
 
-func Report(line int, where, message string) string {
-	report_str := fmt.Sprintf("[line %d] Error%s: %s\n", line, where, message)
-	if PrintFlag {
-		//fmt.Fprintf(os.Stderr, "%s", report_str)
-		panic(report_str)
+// asRuntimeError convierte lo recuperado de un panic en un *RuntimeError.
+func asRuntimeError(r interface{}) *RuntimeError {
+	switch err := r.(type) {
+	case *RuntimeError:
+		return err
+	case error:
+		return &RuntimeError{Kind: INTERNAL_ERROR, Message: err.Error()}
+	}
+	return &RuntimeError{Kind: INTERNAL_ERROR, Message: fmt.Sprint(r)}
+}
+
+// locate completa la linea de los errores que levantan las funciones
+// nativas (que no conocen el token) y convierte los panics de Go.
+func (i *Interpreter) locate(token Token) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if _, ok := r.(runtime.Error); !ok {
+		if _, ok := r.(*RuntimeError); !ok {
+			panic(r)
+		}
+	}
+	err := asRuntimeError(r)
+	if err.Token.Line == 0 {
+		err.Token = token
 	}
-	HasError = true
-	return report_str
+	panic(err)
 }
@@ -0,0 +1,529 @@
+package coati2lang
+
+import (
+	"fmt"
+)
+
+type FunctionType int
+
+const (
+	FUNCTION_NONE FunctionType = iota
+	FUNCTION_FUNCTION
+	FUNCTION_METHOD
+	FUNCTION_INITIALIZER
+)
+
+type ClassType int
+
+const (
+	CLASS_NONE ClassType = iota
+	CLASS_CLASS
+	CLASS_SUBCLASS
+)
+
+type ResolveError struct {
+	Token   Token
+	Message string
+	Hint    string
+}
+
+func (e ResolveError) Error() string {
+	if e.Hint != "" {
+		return fmt.Sprintf("[line %d] Error at '%s': %s %s", e.Token.Line, e.Token.Lexeme, e.Message, e.Hint)
+	}
+	return fmt.Sprintf("[line %d] Error at '%s': %s", e.Token.Line, e.Token.Lexeme, e.Message)
+}
+
+// Resolver recorre el AST antes de ejecutarlo, liga cada uso de una variable
+// con el scope donde fue declarada y reporta los errores estaticos.
+type Resolver struct {
+	interpreter     *Interpreter
+	scopes          []map[string]bool
+	globals         map[string]bool
+	currentFunction FunctionType
+	currentClass    ClassType
+	Errors          []error
+}
+
+func NewResolver(interpreter *Interpreter) *Resolver {
+	globals := make(map[string]bool)
+	for name := range interpreter.globals.Values {
+		globals[name] = true
+	}
+	return &Resolver{
+		interpreter:     interpreter,
+		scopes:          []map[string]bool{},
+		globals:         globals,
+		currentFunction: FUNCTION_NONE,
+		currentClass:    CLASS_NONE,
+	}
+}
+
+func (r *Resolver) Resolve(stmts []Stmt) []error {
+	// Las globales pueden usarse antes de su declaracion (dentro de funciones).
+	for _, stmt := range stmts {
+		switch s := stmt.(type) {
+		case Var:
+			r.globals[s.Name.Lexeme] = true
+		case MultiVar:
+			for _, name := range s.Names {
+				r.globals[name.Lexeme] = true
+			}
+		case Function:
+			r.globals[s.Name.Lexeme] = true
+		case Enum:
+			r.globals[s.Name.Lexeme] = true
+		case Class:
+			r.globals[s.Name.Lexeme] = true
+		}
+	}
+	r.resolveStmts(stmts)
+	r.interpreter.resolved = len(r.Errors) == 0
+	return r.Errors
+}
+
+func (r *Resolver) error(token Token, message string) {
+	r.Errors = append(r.Errors, ResolveError{Token: token, Message: message})
+}
+
+func (r *Resolver) resolveStmts(stmts []Stmt) {
+	for _, stmt := range stmts {
+		r.resolveStmt(stmt)
+	}
+}
+
+func (r *Resolver) resolveStmt(stmt Stmt) {
+	if stmt == nil {
+		return
+	}
+	stmt.AcceptStmt(r)
+}
+
+func (r *Resolver) resolveExpr(expr Expr) {
+	if expr == nil {
+		return
+	}
+	expr.AcceptExpr(r)
+}
+
+func (r *Resolver) resolveExprs(exprs []Expr) {
+	for _, expr := range exprs {
+		r.resolveExpr(expr)
+	}
+}
+
+func (r *Resolver) resolveSelectors(selectors [][]Expr) {
+	for _, selector := range selectors {
+		r.resolveExprs(selector)
+	}
+}
+
+func (r *Resolver) beginScope() {
+	r.scopes = append(r.scopes, make(map[string]bool))
+}
+
+func (r *Resolver) endScope() {
+	r.scopes = r.scopes[:len(r.scopes)-1]
+}
+
+func (r *Resolver) declare(name Token) {
+	if len(r.scopes) == 0 {
+		r.globals[name.Lexeme] = true
+		return
+	}
+	scope := r.scopes[len(r.scopes)-1]
+	if _, ok := scope[name.Lexeme]; ok {
+		r.error(name, "Already a variable with this name in this scope.")
+	}
+	scope[name.Lexeme] = false
+}
+
+func (r *Resolver) define(name Token) {
+	if len(r.scopes) == 0 {
+		return
+	}
+	r.scopes[len(r.scopes)-1][name.Lexeme] = true
+}
+
+func (r *Resolver) resolveLocal(name Token) {
+	for i := len(r.scopes) - 1; i >= 0; i-- {
+		if _, ok := r.scopes[i][name.Lexeme]; ok {
+			r.interpreter.Resolve(name, len(r.scopes)-1-i)
+			return
+		}
+	}
+	// En Lox estricto una global sin definir es un error en tiempo de
+	// ejecucion, como en el libro: puede estar en una rama que no corre.
+	if !r.globals[name.Lexeme] && !r.interpreter.Strict {
+		r.Errors = append(r.Errors, ResolveError{
+			Token:   name,
+			Message: "Undefined variable '" + name.Lexeme + "'.",
+			Hint:    suggest(name.Lexeme, r.visibleNames()),
+		})
+	}
+}
+
+// visibleNames son las variables de todos los scopes abiertos y las globales.
+func (r *Resolver) visibleNames() []string {
+	names := []string{}
+	for _, scope := range r.scopes {
+		for name := range scope {
+			names = append(names, name)
+		}
+	}
+	for name := range r.globals {
+		names = append(names, name)
+	}
+	return names
+}
+
+func (r *Resolver) resolveFunction(function Function, kind FunctionType) {
+	enclosingFunction := r.currentFunction
+	r.currentFunction = kind
+
+	r.beginScope()
+	for _, param := range function.Parameters {
+		r.declare(param)
+		r.define(param)
+	}
+	r.scopes[len(r.scopes)-1]["this"] = true
+	r.resolveStmts(function.Body)
+	r.endScope()
+
+	r.currentFunction = enclosingFunction
+}
+
+func (r *Resolver) resolveInitializers(stmt Var) {
+	r.resolveExpr(stmt.InitializerVal)
+	r.resolveExprs(stmt.InitializerArray)
+	for _, item := range stmt.InitializerMap {
+		r.resolveExpr(item.Key)
+		r.resolveExpr(item.Value)
+	}
+	if function, ok := stmt.InitializerFx.(Function); ok {
+		r.resolveFunction(function, FUNCTION_FUNCTION)
+	}
+}
+
+func (r *Resolver) VisitBlockStmt(stmt Block) interface{} {
+	r.beginScope()
+	r.resolveStmts(stmt.Statements)
+	r.endScope()
+	return nil
+}
+
+func (r *Resolver) VisitVar(stmt Var) interface{} {
+	r.declare(stmt.Name)
+	r.resolveInitializers(stmt)
+	r.define(stmt.Name)
+	return nil
+}
+
+func (r *Resolver) VisitVariableExpr(expr Var) interface{} {
+	if r.interpreter.Strict && expr.Name.Lexeme == "this" && r.currentClass == CLASS_NONE {
+		r.error(expr.Name, "Can't use 'this' outside of a class.")
+		return nil
+	}
+	if expr.Sub {
+		r.VisitVar(expr)
+	} else if len(r.scopes) > 0 {
+		if defined, ok := r.scopes[len(r.scopes)-1][expr.Name.Lexeme]; ok && !defined {
+			r.error(expr.Name, "Can't read local variable in its own initializer.")
+		}
+	}
+	r.resolveLocal(expr.Name)
+	return nil
+}
+
+func (r *Resolver) VisitGetExpr(expr Get) interface{} {
+	r.resolveExpr(expr.Object)
+	return nil
+}
+
+func (r *Resolver) VisitIndexExpr(expr Index) interface{} {
+	r.resolveExpr(expr.Object)
+	r.resolveExprs(expr.Indexes)
+	return nil
+}
+
+func (r *Resolver) VisitSetExpr(expr Set) interface{} {
+	r.resolveExpr(expr.Value)
+	r.resolveExpr(expr.Object)
+	r.resolveSelectors(expr.Selectors)
+	return nil
+}
+
+func (r *Resolver) VisitCompoundAssignExpr(expr CompoundAssign) interface{} {
+	r.resolveExpr(expr.Value)
+	r.resolveExpr(expr.Target)
+	return nil
+}
+
+func (r *Resolver) VisitIncrementExpr(expr Increment) interface{} {
+	r.resolveExpr(expr.Target)
+	return nil
+}
+
+func (r *Resolver) VisitTupleExpr(expr TupleLiteral) interface{} {
+	r.resolveExprs(expr.Elements)
+	return nil
+}
+
+func (r *Resolver) VisitSetLiteralExpr(expr SetLiteral) interface{} {
+	r.resolveExprs(expr.Elements)
+	return nil
+}
+
+func (r *Resolver) VisitMultiAssignExpr(expr MultiAssign) interface{} {
+	r.resolveExpr(expr.Value)
+	r.resolveExprs(expr.Targets)
+	return nil
+}
+
+func (r *Resolver) VisitMultiVarStmt(stmt MultiVar) interface{} {
+	for _, name := range stmt.Names {
+		r.declare(name)
+	}
+	r.resolveExpr(stmt.Value)
+	for _, name := range stmt.Names {
+		r.define(name)
+	}
+	return nil
+}
+
+func (r *Resolver) VisitAssignExpr(expr Assign) interface{} {
+	r.resolveExpr(expr.Value)
+	r.resolveSelectors(expr.Selectors)
+	r.resolveLocal(expr.Name)
+	return nil
+}
+
+func (r *Resolver) VisitFunctionStmt(stmt Function) interface{} {
+	r.declare(stmt.Name)
+	r.define(stmt.Name)
+	r.resolveFunction(stmt, FUNCTION_FUNCTION)
+	return nil
+}
+
+func (r *Resolver) VisitExpressionStmt(stmt Expression) interface{} {
+	r.resolveExpr(stmt.Expression)
+	return nil
+}
+
+func (r *Resolver) VisitIfStmt(stmt If) interface{} {
+	r.resolveExpr(stmt.Condition)
+	r.resolveStmt(stmt.ThenBranch)
+	r.resolveStmt(stmt.ElseBranch)
+	return nil
+}
+
+func (r *Resolver) VisitReturnStmt(stmt Return) interface{} {
+	if r.currentFunction == FUNCTION_NONE {
+		r.error(stmt.Keyword, "Can't return from top-level code.")
+	}
+	if r.currentFunction == FUNCTION_INITIALIZER && stmt.Value != nil {
+		r.error(stmt.Keyword, "Can't return a value from an initializer.")
+	}
+	r.resolveExpr(stmt.Value)
+	return nil
+}
+
+func (r *Resolver) VisitWhileStmt(stmt While) interface{} {
+	r.resolveExpr(stmt.Condition)
+	r.resolveStmt(stmt.Body)
+	return nil
+}
+
+func (r *Resolver) VisitBinaryExpr(expr Binary) interface{} {
+	r.resolveExpr(expr.Left)
+	r.resolveExpr(expr.Right)
+	return nil
+}
+
+func (r *Resolver) VisitCallExpr(expr Call) interface{} {
+	r.resolveExpr(expr.Callee)
+	r.resolveExprs(expr.Arguments)
+	return nil
+}
+
+func (r *Resolver) VisitGroupingExpr(expr Grouping) interface{} {
+	r.resolveExpr(expr.Expression)
+	return nil
+}
+
+func (r *Resolver) VisitGroupingABSExpr(expr GroupingABS) interface{} {
+	r.resolveExpr(expr.Expression)
+	return nil
+}
+
+func (r *Resolver) VisitLiteralExpr(expr Literal) interface{} {
+	switch value := expr.Value.(type) {
+	case []Expr:
+		r.resolveExprs(value)
+	case []ItemVar:
+		for _, item := range value {
+			r.resolveExpr(item.Key)
+			r.resolveExpr(item.Value)
+		}
+	}
+	return nil
+}
+
+func (r *Resolver) VisitLogicalExpr(expr Logical) interface{} {
+	r.resolveExpr(expr.Left)
+	r.resolveExpr(expr.Right)
+	return nil
+}
+
+func (r *Resolver) VisitUnaryExpr(expr Unary) interface{} {
+	r.resolveExpr(expr.Value)
+	return nil
+}
+
+func (r *Resolver) VisitYieldStmt(stmt Yield) interface{} {
+	if r.currentFunction == FUNCTION_NONE {
+		r.error(stmt.Keyword, "Can't yield from top-level code.")
+	}
+	r.resolveExpr(stmt.Value)
+	return nil
+}
+
+func (r *Resolver) VisitTryStmt(stmt Try) interface{} {
+	r.VisitBlockStmt(Block{Statements: stmt.Body})
+	if stmt.Catch != nil {
+		r.beginScope()
+		if stmt.CatchName != nil {
+			r.declare(*stmt.CatchName)
+			r.define(*stmt.CatchName)
+		}
+		r.resolveStmts(stmt.Catch)
+		r.endScope()
+	}
+	if stmt.Finally != nil {
+		r.VisitBlockStmt(Block{Statements: stmt.Finally})
+	}
+	return nil
+}
+
+func (r *Resolver) VisitThrowStmt(stmt Throw) interface{} {
+	r.resolveExpr(stmt.Value)
+	return nil
+}
+
+func (r *Resolver) VisitForInStmt(stmt ForIn) interface{} {
+	r.resolveExpr(stmt.Iterable)
+	r.beginScope()
+	r.declare(stmt.Name)
+	r.define(stmt.Name)
+	r.resolveStmt(stmt.Body)
+	r.endScope()
+	return nil
+}
+
+func (r *Resolver) VisitEnumStmt(stmt Enum) interface{} {
+	r.declare(stmt.Name)
+	names := make(map[string]bool)
+	for _, member := range stmt.Members {
+		if names[member.Name.Lexeme] {
+			r.error(member.Name, "Duplicate member in enum '"+stmt.Name.Lexeme+"'.")
+		}
+		names[member.Name.Lexeme] = true
+		r.resolveExpr(member.Value)
+	}
+	r.define(stmt.Name)
+	return nil
+}
+
+func (r *Resolver) VisitMatchExpr(expr Match) interface{} {
+	r.resolveExpr(expr.Subject)
+	for _, arm := range expr.Arms {
+		r.beginScope()
+		r.resolvePattern(arm.Pattern)
+		r.resolveExpr(arm.Guard)
+		r.resolveExpr(arm.Body)
+		r.endScope()
+	}
+	return nil
+}
+
+func (r *Resolver) resolvePattern(pattern Pattern) {
+	switch p := pattern.(type) {
+	case ValuePattern:
+		r.resolveExpr(p.Value)
+	case BindingPattern:
+		r.declare(p.Name)
+		r.define(p.Name)
+	case ArrayPattern:
+		for _, element := range p.Elements {
+			r.resolvePattern(element)
+		}
+		if p.Rest != nil {
+			r.declare(*p.Rest)
+			r.define(*p.Rest)
+		}
+	case MapPattern:
+		for _, entry := range p.Entries {
+			r.resolvePattern(entry.Pattern)
+		}
+	}
+}
+
+func (r *Resolver) VisitExtendStmt(stmt Extend) interface{} {
+	if _, ok := METHODS[stmt.Type.Lexeme]; !ok {
+		r.error(stmt.Type, "Unknown type '"+stmt.Type.Lexeme+"'.")
+	}
+	for _, method := range stmt.Methods {
+		r.resolveFunction(method, FUNCTION_FUNCTION)
+	}
+	return nil
+}
+
+func (r *Resolver) VisitClassStmt(stmt Class) interface{} {
+	enclosingClass := r.currentClass
+	r.currentClass = CLASS_CLASS
+
+	r.declare(stmt.Name)
+	r.define(stmt.Name)
+
+	if stmt.Superclass != nil {
+		if stmt.Superclass.Name.Lexeme == stmt.Name.Lexeme {
+			r.error(stmt.Superclass.Name, "A class can't inherit from itself.")
+		}
+		r.currentClass = CLASS_SUBCLASS
+		r.resolveExpr(*stmt.Superclass)
+		r.beginScope()
+		r.scopes[len(r.scopes)-1]["super"] = true
+	}
+
+	for _, method := range stmt.Methods {
+		kind := FUNCTION_METHOD
+		if method.Name.Lexeme == "init" {
+			kind = FUNCTION_INITIALIZER
+		}
+		r.resolveFunction(method, kind)
+	}
+
+	if stmt.Superclass != nil {
+		r.endScope()
+	}
+
+	r.currentClass = enclosingClass
+	return nil
+}
+
+func (r *Resolver) VisitSuperExpr(expr Super) interface{} {
+	if r.currentClass == CLASS_NONE {
+		r.error(expr.Keyword, "Can't use 'super' outside of a class.")
+		return nil
+	} else if r.currentClass != CLASS_SUBCLASS {
+		r.error(expr.Keyword, "Can't use 'super' in a class with no superclass.")
+		return nil
+	}
+	r.resolveLocal(expr.Keyword)
+	return nil
+}
+
+func (r *Resolver) VisitPrintStmt(stmt PrintStmt) interface{} {
+	r.resolveExpr(stmt.Expression)
+	return nil
+}
@@ -0,0 +1,101 @@
+package coati2lang
+
+import (
+	"reflect"
+	"testing"
+)
+
+// resolveErrors parsea source y devuelve los errores del resolver.
+func resolveErrors(t *testing.T, source string, strict bool) []string {
+	t.Helper()
+	tokens, errs := ScanTokens(source)
+	parser := NewParser(tokens)
+	parser.Strict = strict
+	stmts, parseErrs := parser.Parse()
+	if errs = append(errs, parseErrs...); len(errs) > 0 {
+		t.Fatalf("%s: %v", source, errs)
+	}
+	interp := NewInterpreter(stmts)
+	interp.Strict = strict
+	results := []string{}
+	for _, err := range NewResolver(interp).Resolve(stmts) {
+		results = append(results, err.Error())
+	}
+	return results
+}
+
+func TestResolverErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{"globals before their declaration", "fun f() { return g; }\nvar g = 1;\nprintln(f());", nil},
+		{"undefined variable", "var count = 1;\nprintln(cuont);", []string{
+			"[line 2] Error at 'cuont': Undefined variable 'cuont'. Did you mean 'count'?",
+		}},
+		{"redeclared local", "fun f() {\n  var a = 1;\n  var a = 2;\n}", []string{
+			"[line 3] Error at 'a': Already a variable with this name in this scope.",
+		}},
+		{"own initializer", "fun f() {\n  var a = 1;\n  {\n    var a = a;\n  }\n}", []string{
+			"[line 4] Error at 'a': Can't read local variable in its own initializer.",
+		}},
+		{"top-level return", "return 1;", []string{
+			"[line 1] Error at 'return': Can't return from top-level code.",
+		}},
+		{"return value from init", "class A {\n  init() {\n    return 1;\n  }\n}", []string{
+			"[line 3] Error at 'return': Can't return a value from an initializer.",
+		}},
+		{"top-level yield", "yield 1;", []string{
+			"[line 1] Error at 'yield': Can't yield from top-level code.",
+		}},
+		{"inherit from itself", "class A < A {}", []string{
+			"[line 1] Error at 'A': A class can't inherit from itself.",
+		}},
+		{"super outside a class", "fun f() {\n  super.m();\n}", []string{
+			"[line 2] Error at 'super': Can't use 'super' outside of a class.",
+		}},
+		{"super without superclass", "class A {\n  m() {\n    super.m();\n  }\n}", []string{
+			"[line 3] Error at 'super': Can't use 'super' in a class with no superclass.",
+		}},
+		{"duplicate enum member", "enum Color { Red, Red }", []string{
+			"[line 1] Error at 'Red': Duplicate member in enum 'Color'.",
+		}},
+		{"extend unknown type", "extend strin {\n  shout() { return 1; }\n}", []string{
+			"[line 1] Error at 'strin': Unknown type 'strin'.",
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := resolveErrors(t, c.source, false)
+			if len(c.want) == 0 && len(got) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("%s\ngot  %q\nwant %q", c.source, got, c.want)
+			}
+		})
+	}
+}
+
+func TestResolverStrict(t *testing.T) {
+	cases := []struct {
+		source string
+		want   []string
+	}{
+		// En modo estricto una global sin definir falla al ejecutarse.
+		{"if (false) {\n  print notDefined;\n}", nil},
+		{"print this;", []string{"[line 1] Error at 'this': Can't use 'this' outside of a class."}},
+		{"fun f() {\n  print this;\n}", []string{"[line 2] Error at 'this': Can't use 'this' outside of a class."}},
+		{"class A {\n  m() {\n    print this;\n  }\n}", nil},
+	}
+	for _, c := range cases {
+		got := resolveErrors(t, c.source, true)
+		if len(c.want) == 0 && len(got) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s\ngot  %q\nwant %q", c.source, got, c.want)
+		}
+	}
+}
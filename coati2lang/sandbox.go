@@ -0,0 +1,185 @@
+package coati2lang
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Capability es un permiso con la forma dominio:accion:recurso, por ejemplo
+// "fs:read:/data", "os:env" o "net:none". Sin recurso vale para todos; en
+// el dominio fs el recurso es un directorio (con los symlinks resueltos) y
+// vale para todo lo que cuelga de el.
+type Capability struct {
+	Domain   string
+	Action   string
+	Resource string
+}
+
+func ParseCapability(spec string) (Capability, error) {
+	parts := strings.SplitN(strings.TrimSpace(spec), ":", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return Capability{}, fmt.Errorf("invalid capability '%s', expected domain:action[:resource]", spec)
+	}
+	capability := Capability{Domain: parts[0], Action: parts[1]}
+	if len(parts) == 3 {
+		capability.Resource = parts[2]
+		if capability.Domain == "fs" {
+			capability.Resource = realPath(capability.Resource)
+		}
+	}
+	return capability, nil
+}
+
+func (c Capability) String() string {
+	if c.Resource == "" {
+		return c.Domain + ":" + c.Action
+	}
+	return c.Domain + ":" + c.Action + ":" + c.Resource
+}
+
+func cleanPath(path string) string {
+	if absolute, err := filepath.Abs(path); err == nil {
+		return absolute
+	}
+	return filepath.Clean(path)
+}
+
+// realPath es la ruta absoluta de path con los symlinks resueltos. Si el
+// archivo todavia no existe (writefile) se resuelve el directorio.
+func realPath(path string) string {
+	absolute := cleanPath(path)
+	if resolved, err := filepath.EvalSymlinks(absolute); err == nil {
+		return resolved
+	}
+	if dir, err := filepath.EvalSymlinks(filepath.Dir(absolute)); err == nil {
+		return filepath.Join(dir, filepath.Base(absolute))
+	}
+	return absolute
+}
+
+// Capabilities es el conjunto de permisos de un interprete. Sin
+// Capabilities (nil) las nativas privilegiadas estan todas negadas.
+type Capabilities []Capability
+
+// AllCapabilities da todos los permisos; es lo que hace "-allow all".
+var AllCapabilities = Capabilities{{Domain: "*", Action: "*"}}
+
+// ParseCapabilities lee una lista separada por comas: "fs:read:/data,os:env".
+// "all" da todos los permisos y "none" ninguno.
+func ParseCapabilities(specs string) (Capabilities, error) {
+	capabilities := Capabilities{}
+	for _, spec := range strings.Split(specs, ",") {
+		if strings.TrimSpace(spec) == "" || strings.TrimSpace(spec) == "none" {
+			continue
+		}
+		if strings.TrimSpace(spec) == "all" {
+			capabilities = append(capabilities, AllCapabilities...)
+			continue
+		}
+		capability, err := ParseCapability(spec)
+		if err != nil {
+			return nil, err
+		}
+		capabilities = append(capabilities, capability)
+	}
+	return capabilities, nil
+}
+
+// Allows indica si el pedido esta cubierto por algun permiso. "dominio:none"
+// niega todo el dominio aunque haya otros permisos para el.
+func (c Capabilities) Allows(request Capability) bool {
+	allowed := false
+	for _, grant := range c {
+		if grant.Domain != "*" && grant.Domain != request.Domain {
+			continue
+		}
+		if grant.Action == "none" {
+			return false
+		}
+		if grant.Action != "*" && grant.Action != request.Action {
+			continue
+		}
+		if grant.covers(request.Resource) {
+			allowed = true
+		}
+	}
+	return allowed
+}
+
+func (c Capability) covers(resource string) bool {
+	if c.Resource == "" || c.Resource == "*" || c.Resource == resource {
+		return true
+	}
+	if c.Domain == "fs" {
+		return strings.HasPrefix(resource, strings.TrimSuffix(c.Resource, string(filepath.Separator))+string(filepath.Separator))
+	}
+	return strings.HasSuffix(c.Resource, "*") && strings.HasPrefix(resource, strings.TrimSuffix(c.Resource, "*"))
+}
+
+// AuditEvent describe una llamada a una funcion nativa con permisos, se haya
+// permitido o no.
+type AuditEvent struct {
+	Native     string
+	Capability Capability
+	Allowed    bool
+	Line       int
+}
+
+func (e AuditEvent) String() string {
+	verdict := "denied"
+	if e.Allowed {
+		verdict = "allowed"
+	}
+	return fmt.Sprintf("[line %d] %s %s: %s", e.Line, e.Native, e.Capability, verdict)
+}
+
+// PrivilegedNative es una funcion nativa que necesita un permiso para
+// ejecutarse; Requires arma el pedido a partir de los argumentos.
+type PrivilegedNative interface {
+	NativeFunction
+	Requires(arguments []interface{}) Capability
+}
+
+// authorize valida los argumentos de una nativa privilegiada y sus
+// permisos. Se llama desde el Call de la nativa, asi no se puede esquivar
+// pasandola como callback (arr.map(readfile)).
+func (i *Interpreter) authorize(name string, native PrivilegedNative, arguments []interface{}) Capability {
+	native.Signature().check(Token{}, name, arguments)
+	request := native.Requires(arguments)
+	allowed := i.Capabilities.Allows(request)
+	if i.AuditHook != nil {
+		line := 0
+		if len(i.frames) > 0 {
+			line = i.frames[len(i.frames)-1].Line
+		}
+		i.AuditHook(AuditEvent{Native: name, Capability: request, Allowed: allowed, Line: line})
+	}
+	if !allowed {
+		raise(Token{}, PERMISSION_ERROR, "%s requires the capability '%s'.", name, request)
+	}
+	return request
+}
+
+// openAuthorized abre el archivo de un pedido ya autorizado. Se abre la ruta
+// resuelta y despues se comprueba que el archivo abierto siga siendo el de
+// esa ruta: si un symlink cambio entre el chequeo y el open, se niega.
+func (i *Interpreter) openAuthorized(name string, request Capability, flag int) *os.File {
+	if flag&os.O_CREATE != 0 {
+		if info, err := os.Lstat(request.Resource); err == nil && info.Mode()&os.ModeSymlink != 0 {
+			raise(Token{}, PERMISSION_ERROR, "%s requires the capability '%s'.", name, request)
+		}
+	}
+	file, err := os.OpenFile(request.Resource, flag, 0644)
+	if err != nil {
+		raise(Token{}, IO_ERROR, "%s: %s.", name, err)
+	}
+	opened, err := file.Stat()
+	current, statErr := os.Stat(request.Resource)
+	if err != nil || statErr != nil || realPath(request.Resource) != request.Resource || !os.SameFile(opened, current) {
+		file.Close()
+		raise(Token{}, PERMISSION_ERROR, "%s requires the capability '%s'.", name, request)
+	}
+	return file
+}
@@ -0,0 +1,144 @@
+package coati2lang
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestCapabilitiesAllows(t *testing.T) {
+	capabilities, err := ParseCapabilities("fs:read:/data,os:env,net:none")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		request Capability
+		allowed bool
+	}{
+		{Capability{Domain: "fs", Action: "read", Resource: "/data"}, true},
+		{Capability{Domain: "fs", Action: "read", Resource: "/data/a/b.txt"}, true},
+		{Capability{Domain: "fs", Action: "read", Resource: "/data2/a.txt"}, false},
+		{Capability{Domain: "fs", Action: "read", Resource: "/"}, false},
+		{Capability{Domain: "fs", Action: "write", Resource: "/data/a.txt"}, false},
+		{Capability{Domain: "fs", Action: "read", Resource: cleanPath("/data/../etc/passwd")}, false},
+		{Capability{Domain: "os", Action: "env", Resource: "HOME"}, true},
+		{Capability{Domain: "net", Action: "connect", Resource: "example.com"}, false},
+	}
+	for _, c := range cases {
+		if got := capabilities.Allows(c.request); got != c.allowed {
+			t.Errorf("Allows(%s) = %t, want %t", c.request, got, c.allowed)
+		}
+	}
+
+	var none Capabilities
+	if none.Allows(Capability{Domain: "os", Action: "env", Resource: "HOME"}) {
+		t.Error("nil Capabilities must deny everything")
+	}
+	if !AllCapabilities.Allows(Capability{Domain: "fs", Action: "write", Resource: "/tmp/x"}) {
+		t.Error("AllCapabilities must allow everything")
+	}
+}
+
+// runSandboxed corre source con los permisos dados y devuelve la global result.
+func runSandboxed(t *testing.T, source string, capabilities Capabilities) interface{} {
+	t.Helper()
+	tokens, errs := ScanTokens(source)
+	parser := NewParser(tokens)
+	stmts, parseErrs := parser.Parse()
+	if errs = append(errs, parseErrs...); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	interp := NewInterpreter(stmts)
+	interp.Capabilities = capabilities
+	if errs := NewResolver(interp).Resolve(stmts); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if _, err := interp.Interpret(); err != nil {
+		t.Fatal(err)
+	}
+	result, _ := interp.globals.Get("result")
+	return result
+}
+
+func readScript(path string) string {
+	return "var result = nil;\ntry { result = readfile(" + strconv.Quote(path) + "); } catch (e) { result = e.kind; }\n"
+}
+
+func TestReadfileSandbox(t *testing.T) {
+	root := t.TempDir()
+	data := filepath.Join(root, "data")
+	if err := os.Mkdir(data, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(data, "ok.txt"), []byte("ok"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	secret := filepath.Join(root, "secret.txt")
+	if err := os.WriteFile(secret, []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(secret, filepath.Join(data, "link")); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+	if err := os.Symlink(data, filepath.Join(root, "alias")); err != nil {
+		t.Fatal(err)
+	}
+
+	capabilities, err := ParseCapabilities("fs:read:" + data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		path string
+		want interface{}
+	}{
+		{filepath.Join(data, "ok.txt"), "ok"},
+		{filepath.Join(data, "..", "secret.txt"), string(PERMISSION_ERROR)},
+		{filepath.Join(data, "link"), string(PERMISSION_ERROR)},
+		{filepath.Join(root, "alias", "ok.txt"), "ok"},
+		{filepath.Join(root, "alias", "link"), string(PERMISSION_ERROR)},
+	}
+	for _, c := range cases {
+		if got := runSandboxed(t, readScript(c.path), capabilities); got != c.want {
+			t.Errorf("readfile(%s) = %v, want %v", c.path, got, c.want)
+		}
+	}
+
+	// Sin permisos las nativas privilegiadas estan negadas.
+	if got := runSandboxed(t, readScript(filepath.Join(data, "ok.txt")), nil); got != string(PERMISSION_ERROR) {
+		t.Errorf("readfile without capabilities = %v, want %s", got, PERMISSION_ERROR)
+	}
+
+	// El directorio permitido puede ser un symlink.
+	aliased, err := ParseCapabilities("fs:read:" + filepath.Join(root, "alias"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := runSandboxed(t, readScript(filepath.Join(data, "ok.txt")), aliased); got != "ok" {
+		t.Errorf("readfile through an aliased grant = %v, want ok", got)
+	}
+}
+
+func TestWritefileSymlink(t *testing.T) {
+	root := t.TempDir()
+	data := filepath.Join(root, "data")
+	if err := os.Mkdir(data, 0755); err != nil {
+		t.Fatal(err)
+	}
+	outside := filepath.Join(root, "outside.txt")
+	if err := os.Symlink(outside, filepath.Join(data, "dangling")); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+	capabilities, err := ParseCapabilities("fs:write:" + data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := "var result = nil;\ntry { writefile(" + strconv.Quote(filepath.Join(data, "dangling")) + ", \"x\"); } catch (e) { result = e.kind; }\n"
+	if got := runSandboxed(t, source, capabilities); got != string(PERMISSION_ERROR) {
+		t.Errorf("writefile through a dangling symlink = %v, want %s", got, PERMISSION_ERROR)
+	}
+	if _, err := os.Stat(outside); err == nil {
+		t.Error("writefile created a file outside the granted directory")
+	}
+}
@@ -2,6 +2,8 @@ package coati2lang
 
 import (
 	"strconv"
+	"strings"
+	"unicode/utf8"
 )
 
 var keywords = map[string]TokenType{
@@ -39,14 +41,37 @@ var keywords = map[string]TokenType{
 	"extends":    EXTENDS,
 	"let":        LET,
 	"const":      CONST,
+	"yield":      YIELD,
+	"in":         IN,
+	"enum":       ENUM,
+	"match":      MATCH,
+	"extend":     EXTEND,
+}
+
+// Comment es un comentario // con su posicion; el linter lee ahi sus
+// directivas. Column es la de las barras.
+type Comment struct {
+	Line   int
+	Column int
+	Text   string
 }
 
 type Scanner struct {
-	Source  string
-	Tokens  []Token
-	Start   int
-	Current int
-	Line    int
+	Source    string
+	Tokens    []Token
+	Errors    []error
+	Comments  []Comment
+	Start     int
+	Current   int
+	Line      int
+	LineStart int
+	Column    int
+	// StartLine es la linea donde empieza el lexema actual; un string de
+	// varias lineas termina en otra.
+	StartLine int
+	// Strict toma los strings tal cual, como el Lox del libro: sin
+	// secuencias de escape.
+	Strict bool
 }
 
 func NewScanner(source string) *Scanner {
@@ -63,13 +88,34 @@ func (s *Scanner) ScanTokens() []Token {
 	for !s.isAtEnd() {
 		// Estamos al comienzo del siguiente lexema.
 		s.Start = s.Current
+		s.Column = s.column(s.Current)
+		s.StartLine = s.Line
 		s.scanToken() // Asumiendo que 'scanToken' está definido y toma estos argumentos
 	}
 
-	s.Tokens = append(s.Tokens, NewToken(EOF, "", nil, s.Line))
+	eof := NewToken(EOF, "", nil, s.Line)
+	eof.Column = s.column(s.Current)
+	s.Tokens = append(s.Tokens, eof)
 	return s.Tokens
 }
 
+// column cuenta en runas desde el comienzo de la linea hasta offset: los
+// editores no cuentan los bytes de un caracter como "é" por separado.
+func (s *Scanner) column(offset int) int {
+	return utf8.RuneCountInString(s.Source[s.LineStart:offset]) + 1
+}
+
+// error registra un error en el lexema actual y sigue escaneando, para
+// reportar todos los errores juntos. Si el lexema ocupa varias lineas el
+// rango se queda en la primera.
+func (s *Scanner) error(message string) {
+	lexeme := s.Source[s.Start:s.Current]
+	if newline := strings.IndexByte(lexeme, '\n'); newline >= 0 {
+		lexeme = lexeme[:newline]
+	}
+	s.Errors = append(s.Errors, SyntaxError{Line: s.StartLine, Column: s.Column, Length: utf8.RuneCountInString(lexeme), Message: message})
+}
+
 func (s *Scanner) isAtEnd() bool {
 	return s.Current >= len(s.Source)
 }
@@ -95,7 +141,19 @@ func (s *Scanner) scanToken() {
 	case ':':
 		s.addToken(COLON, ":")
 	case '?':
-		s.addToken(QUESTION, "?")
+		if s.match('.') {
+			s.addToken(QUESTION_DOT, "?.")
+		} else if s.match('[') {
+			s.addToken(QUESTION_BRACKET, "?[")
+		} else {
+			s.addToken(QUESTION, "?")
+		}
+	case '#':
+		if s.match('{') {
+			s.addToken(HASH_BRACE, "#{")
+		} else {
+			s.error("Unexpected character.")
+		}
 	case '^':
 		s.addToken(CARET, "^")
 	case '|':
@@ -117,12 +175,16 @@ func (s *Scanner) scanToken() {
 	case '-':
 		if s.match('-') {
 			s.addToken(MINUS_MINUS, "--")
+		} else if s.match('=') {
+			s.addToken(MINUS_EQUAL, "-=")
 		} else {
 			s.addToken(MINUS, "-")
 		}
 	case '+':
 		if s.match('+') {
 			s.addToken(PLUS_PLUS, "++")
+		} else if s.match('=') {
+			s.addToken(PLUS_EQUAL, "+=")
 		} else {
 			s.addToken(PLUS, "+")
 		}
@@ -130,7 +192,13 @@ func (s *Scanner) scanToken() {
 		s.addToken(SEMICOLON, ";")
 	case '*':
 		if s.match('*') {
-			s.addToken(STAR_STAR, "**")
+			if s.match('=') {
+				s.addToken(STAR_STAR_EQUAL, "**=")
+			} else {
+				s.addToken(STAR_STAR, "**")
+			}
+		} else if s.match('=') {
+			s.addToken(STAR_EQUAL, "*=")
 		} else {
 			s.addToken(STAR, "*")
 		}
@@ -150,7 +218,7 @@ func (s *Scanner) scanToken() {
 		}
 	case '<':
 		if s.match('=') {
-			s.addToken(LESS_EQUAL, ">=")
+			s.addToken(LESS_EQUAL, "<=")
 		} else if s.match('>') {
 			s.addToken(BANG_EQUAL, "<>")
 		} else if s.match('<') {
@@ -172,6 +240,9 @@ func (s *Scanner) scanToken() {
 			for s.peek() != '\n' && !s.isAtEnd() {
 				s.advance()
 			}
+			s.Comments = append(s.Comments, Comment{Line: s.Line, Column: s.Column, Text: s.Source[s.Start+2 : s.Current]})
+		} else if s.match('=') {
+			s.addToken(SLASH_EQUAL, "/=")
 		} else {
 			s.addToken(SLASH, "/")
 		}
@@ -181,6 +252,7 @@ func (s *Scanner) scanToken() {
 		// Ignore whitespace.
 	case '\n':
 		s.Line++
+		s.LineStart = s.Current
 	case '"':
 		if s.peek() == '"' && s.peekNext() == '"' {
 			s.multiline_string()
@@ -194,7 +266,7 @@ func (s *Scanner) scanToken() {
 		} else if s.isAlpha(c) {
 			s.identifier()
 		} else {
-			Errors(s.Line, "Unexpected character.")
+			s.error("Unexpected character.")
 		}
 	}
 }
@@ -220,7 +292,7 @@ func (s *Scanner) number() interface{} {
 
 	value, err := strconv.ParseFloat(s.Source[s.Start:s.Current], 64)
 	if err != nil {
-		Errors(s.Line, "Error parsing number.")
+		s.error("Error parsing number.")
 	}
 
 	s.addToken(NUMBER, value)
@@ -275,17 +347,18 @@ func (s *Scanner) multiline_string() {
 	for s.peek() != '"' && !s.isAtEnd() {
 		if s.peek() == '\n' {
 			s.Line++
+			s.LineStart = s.Current + 1
 		}
 		s.advance()
 	}
-	s.advance()
-	s.advance()
-	s.advance()
 
 	if s.isAtEnd() {
-		Errors(s.Line, "Unterminated string.")
+		s.error("Unterminated string.")
 		return
 	}
+	s.advance()
+	s.advance()
+	s.advance()
 
 	value := s.Source[s.Start+3 : s.Current-3]
 
@@ -296,20 +369,25 @@ func (s *Scanner) string() {
 	for s.peek() != '"' && !s.isAtEnd() {
 		if s.peek() == '\n' {
 			s.Line++
+			s.LineStart = s.Current + 1
 		}
 		s.advance()
 	}
-	s.advance()
 
 	if s.isAtEnd() {
-		Errors(s.Line, "Unterminated string.")
+		s.error("Unterminated string.")
 		return
 	}
+	s.advance()
 
 	value := s.Source[s.Start+1 : s.Current-1]
+	if s.Strict {
+		s.addToken(STRING, value)
+		return
+	}
 	value, err := strconv.Unquote("\"" + value + "\"")
 	if err != nil {
-		Errors(s.Line, "Error parsing string.")
+		s.error("Error parsing string.")
 	}
 	s.addToken(STRING, value)
 }
@@ -334,5 +412,6 @@ func (s *Scanner) advance() rune {
 func (s *Scanner) addToken(tokenType TokenType, literal interface{}) {
 	text := s.Source[s.Start:s.Current]
 	token := NewToken(tokenType, text, literal, s.Line)
+	token.Column = s.Column
 	s.Tokens = append(s.Tokens, token)
 }
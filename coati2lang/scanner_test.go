@@ -0,0 +1,21 @@
+package coati2lang
+
+import "testing"
+
+func TestScannerColumnsCountRunes(t *testing.T) {
+	tokens, errs := ScanTokens("var s = \"ééé\"; println(zz);\n\"ü")
+	if len(errs) != 1 {
+		t.Fatalf("got errors %v, want one unterminated string", errs)
+	}
+	for _, token := range tokens {
+		if token.Lexeme == "zz" && token.Column != 24 {
+			t.Errorf("zz at column %d, want 24", token.Column)
+		}
+	}
+	if err := errs[0].(SyntaxError); err.Line != 2 || err.Column != 1 || err.Length != 2 {
+		t.Errorf("unterminated string at %d:%d length %d, want 2:1 length 2", err.Line, err.Column, err.Length)
+	}
+	if eof := tokens[len(tokens)-1]; eof.Column != 3 {
+		t.Errorf("EOF at column %d, want 3", eof.Column)
+	}
+}
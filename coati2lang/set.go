@@ -0,0 +1,220 @@
+package coati2lang
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+func init() {
+	GlobalFx["set"] = SetFx{}
+	METHODS["set"] = SET_FX_MAP
+}
+
+// LoxSet guarda los elementos en orden de insercion para imprimir e iterar
+// siempre igual.
+type LoxSet struct {
+	items  map[interface{}]bool
+	order  []interface{}
+	frozen bool
+}
+
+var SET_FX_MAP = map[string]Method{
+	"add":          {Signature: sig("(...values: any) -> set", "Adds values; returns the set."), Fx: setAdd, Mutates: true},
+	"remove":       {Signature: sig("(value: any) -> boolean", "Removes value; false if it was not present."), Fx: setRemove, Mutates: true},
+	"has":          {Signature: sig("(value: any) -> boolean", "Whether value is in the set."), Fx: setHas},
+	"len":          {Signature: sig("() -> number", "Number of elements."), Fx: setLen},
+	"values":       {Signature: sig("() -> array", "Elements in insertion order."), Fx: setValues},
+	"union":        {Signature: sig("(other: iterable) -> set", "Elements in either."), Fx: setUnion},
+	"intersection": {Signature: sig("(other: iterable) -> set", "Elements in both."), Fx: setIntersection},
+	"difference":   {Signature: sig("(other: iterable) -> set", "Elements not in other."), Fx: setDifference},
+	"subset":       {Signature: sig("(other: iterable) -> boolean", "Whether every element is in other."), Fx: setSubset},
+	"superset":     {Signature: sig("(other: iterable) -> boolean", "Whether every element of other is in the set."), Fx: setSuperset},
+}
+
+func NewLoxSet(values ...interface{}) *LoxSet {
+	set := &LoxSet{items: make(map[interface{}]bool)}
+	for _, value := range values {
+		set.Add(value)
+	}
+	return set
+}
+
+func (s *LoxSet) String() string {
+	items := make([]string, len(s.order))
+	for index, item := range s.order {
+		items[index] = fmt.Sprint(item)
+	}
+	return "#{" + strings.Join(items, ", ") + "}"
+}
+
+// hashable dice si value puede ser clave de un set: arrays, mapas y
+// funciones (que guardan slices) no lo son.
+func hashable(value interface{}) bool {
+	if _, ok := value.(*LoxArray); ok {
+		return false
+	}
+	return value == nil || reflect.TypeOf(value).Comparable()
+}
+
+// checkKey levanta un TypeError si key no puede ser clave de un mapa.
+func checkKey(token Token, key interface{}) {
+	if !hashable(key) {
+		raise(token, TYPE_ERROR, "Can't use a value of type '%s' as a map key.", typeName(key))
+	}
+}
+
+func (s *LoxSet) Add(value interface{}) {
+	if !hashable(value) {
+		raise(Token{}, TYPE_ERROR, "Can't add a value of type '%s' to a set.", typeName(value))
+	}
+	if s.items[value] {
+		return
+	}
+	s.items[value] = true
+	s.order = append(s.order, value)
+}
+
+func (s *LoxSet) Remove(value interface{}) bool {
+	if !s.Has(value) {
+		return false
+	}
+	delete(s.items, value)
+	for index, item := range s.order {
+		if item == value {
+			s.order = append(s.order[:index], s.order[index+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+func (s *LoxSet) Has(value interface{}) bool {
+	if !hashable(value) {
+		return false
+	}
+	return s.items[value]
+}
+
+func (s *LoxSet) Len() int {
+	return len(s.order)
+}
+
+func (s *LoxSet) Values() []interface{} {
+	values := make([]interface{}, len(s.order))
+	copy(values, s.order)
+	return values
+}
+
+func (s *LoxSet) Clone() *LoxSet {
+	return NewLoxSet(s.order...)
+}
+
+// toSet acepta otro set o cualquier valor iterable como argumento.
+func (i *Interpreter) toSet(value interface{}) *LoxSet {
+	if set, ok := value.(*LoxSet); ok {
+		return set
+	}
+	set := NewLoxSet()
+	i.iterate(value, Token{}, func(item interface{}) {
+		set.Add(item)
+	})
+	return set
+}
+
+var setSignature = sig("(items?: iterable) -> set", "New set, optionally with the elements of items.")
+
+type SetFx struct {
+}
+
+// Call crea un set vacio o con los elementos de un iterable: set([1, 2, 2]).
+func (c SetFx) Call(interpreter *Interpreter, arguments []interface{}, this interface{}) interface{} {
+	if len(arguments) == 0 {
+		return NewLoxSet()
+	}
+	return interpreter.toSet(arguments[0]).Clone()
+}
+
+func (c SetFx) Arity() int {
+	return setSignature.Arity()
+}
+
+func (c SetFx) Signature() *Signature {
+	return setSignature
+}
+
+func (i *Interpreter) VisitSetLiteralExpr(expr SetLiteral) interface{} {
+	set := NewLoxSet()
+	for _, element := range expr.Elements {
+		set.Add(i.full_evaluate(element))
+	}
+	return i.charge(expr.Brace, set)
+}
+
+func setAdd(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	set := this.(*LoxSet)
+	for _, arg := range args {
+		set.Add(arg)
+	}
+	return set
+}
+
+func setRemove(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	return this.(*LoxSet).Remove(args[0])
+}
+
+func setHas(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	return this.(*LoxSet).Has(args[0])
+}
+
+func setLen(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	return float64(this.(*LoxSet).Len())
+}
+
+func setValues(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	return NewLoxArray(this.(*LoxSet).Values())
+}
+
+func setUnion(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	result := this.(*LoxSet).Clone()
+	for _, item := range interpreter.toSet(args[0]).order {
+		result.Add(item)
+	}
+	return result
+}
+
+func setIntersection(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	other := interpreter.toSet(args[0])
+	result := NewLoxSet()
+	for _, item := range this.(*LoxSet).order {
+		if other.Has(item) {
+			result.Add(item)
+		}
+	}
+	return result
+}
+
+func setDifference(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	other := interpreter.toSet(args[0])
+	result := NewLoxSet()
+	for _, item := range this.(*LoxSet).order {
+		if !other.Has(item) {
+			result.Add(item)
+		}
+	}
+	return result
+}
+
+func setSubset(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	other := interpreter.toSet(args[0])
+	for _, item := range this.(*LoxSet).order {
+		if !other.Has(item) {
+			return false
+		}
+	}
+	return true
+}
+
+func setSuperset(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	return setSubset(interpreter, interpreter.toSet(args[0]), []interface{}{this})
+}
@@ -0,0 +1,305 @@
+package coati2lang
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Param describe un parametro de una funcion nativa. Types son nombres de
+// typeName ("number", "string"...) o los alias "any", "callable" e
+// "iterable"; un parametro Variadic recibe todos los argumentos restantes.
+type Param struct {
+	Name     string
+	Types    []string
+	Optional bool
+	Variadic bool
+}
+
+// Signature es la firma declarada de una funcion o metodo nativo: el
+// interprete valida los argumentos antes de llamar y help() la muestra.
+type Signature struct {
+	Params  []Param
+	Returns string
+	Doc     string
+}
+
+// NativeFunction es una funcion nativa global con firma declarada.
+type NativeFunction interface {
+	LoxCallable
+	Signature() *Signature
+}
+
+// sig arma una Signature a partir de su declaracion, por ejemplo
+// "(sep: string, limit?: number) -> array" o "(...values: any) -> nil".
+func sig(declaration string, doc string) *Signature {
+	signature, err := ParseSignature(declaration)
+	if err != nil {
+		panic(err)
+	}
+	signature.Doc = doc
+	return signature
+}
+
+func ParseSignature(declaration string) (*Signature, error) {
+	declaration = strings.TrimSpace(declaration)
+	end := strings.Index(declaration, ")")
+	if !strings.HasPrefix(declaration, "(") || end < 0 {
+		return nil, fmt.Errorf("invalid signature '%s'", declaration)
+	}
+	signature := &Signature{Params: []Param{}, Returns: "any"}
+	if rest := strings.TrimSpace(declaration[end+1:]); rest != "" {
+		if !strings.HasPrefix(rest, "->") {
+			return nil, fmt.Errorf("invalid signature '%s'", declaration)
+		}
+		signature.Returns = strings.TrimSpace(rest[2:])
+	}
+
+	params := strings.TrimSpace(declaration[1:end])
+	if params == "" {
+		return signature, nil
+	}
+	for _, text := range strings.Split(params, ",") {
+		param := Param{Types: []string{"any"}}
+		name, types, found := strings.Cut(strings.TrimSpace(text), ":")
+		if found {
+			param.Types = strings.Split(strings.ReplaceAll(types, " ", ""), "|")
+		}
+		name = strings.TrimSpace(name)
+		if strings.HasPrefix(name, "...") {
+			param.Variadic, name = true, name[3:]
+		}
+		if strings.HasSuffix(name, "?") {
+			param.Optional, name = true, name[:len(name)-1]
+		}
+		if name == "" {
+			return nil, fmt.Errorf("invalid signature '%s'", declaration)
+		}
+		param.Name = name
+		signature.Params = append(signature.Params, param)
+	}
+	for index, param := range signature.Params {
+		if param.Variadic && index != len(signature.Params)-1 {
+			return nil, fmt.Errorf("variadic parameter '%s' must be the last one", param.Name)
+		}
+	}
+	return signature, nil
+}
+
+func (s *Signature) String() string {
+	params := make([]string, len(s.Params))
+	for index, param := range s.Params {
+		name := param.Name
+		if param.Variadic {
+			name = "..." + name
+		}
+		if param.Optional {
+			name += "?"
+		}
+		params[index] = name + ": " + strings.Join(param.Types, "|")
+	}
+	return "(" + strings.Join(params, ", ") + ") -> " + s.Returns
+}
+
+// bounds devuelve la cantidad minima y maxima de argumentos; max es -1
+// cuando hay un parametro variadico.
+func (s *Signature) bounds() (int, int) {
+	min, max := 0, len(s.Params)
+	for _, param := range s.Params {
+		if param.Variadic {
+			max = -1
+		}
+		if !param.Optional && !param.Variadic {
+			min++
+		}
+	}
+	return min, max
+}
+
+// Arity es la aridad para LoxCallable: -1 si acepta una cantidad variable.
+func (s *Signature) Arity() int {
+	min, max := s.bounds()
+	if min != max {
+		return -1
+	}
+	return min
+}
+
+// check valida cantidad y tipos de los argumentos de name.
+func (s *Signature) check(token Token, name string, arguments []interface{}) {
+	min, max := s.bounds()
+	if len(arguments) < min || (max >= 0 && len(arguments) > max) {
+		expected := fmt.Sprint(min)
+		switch {
+		case max < 0:
+			expected = fmt.Sprintf("at least %d", min)
+		case max != min:
+			expected = fmt.Sprintf("%d to %d", min, max)
+		}
+		raise(token, ARGUMENT_ERROR, "Expected %s arguments but got %d.", expected, len(arguments))
+	}
+
+	for index, argument := range arguments {
+		param := s.Params[len(s.Params)-1]
+		if index < len(s.Params) {
+			param = s.Params[index]
+		}
+		if !acceptsType(param.Types, argument) {
+			raise(token, TYPE_ERROR, "Argument '%s' of %s must be %s, got %s.", param.Name, name, strings.Join(param.Types, " or "), typeName(argument))
+		}
+		// Las nativas leen los numeros como float64; los int que llegan de
+		// funciones registradas desde Go se convierten aca.
+		if isNumber(argument) {
+			arguments[index] = toFloat(argument)
+		}
+	}
+}
+
+func acceptsType(types []string, value interface{}) bool {
+	kind := typeName(value)
+	for _, accepted := range types {
+		switch accepted {
+		case "any", kind:
+			return true
+		case "callable":
+			if _, ok := value.(LoxCallable); ok {
+				return true
+			}
+		case "iterable":
+			switch kind {
+			case "string", "array", "tuple", "map", "set", "generator":
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Completion es una sugerencia para editores y para help().
+type Completion struct {
+	Name      string
+	Signature string
+	Doc       string
+}
+
+// Completions devuelve las funciones globales (kind "") o los metodos de
+// un tipo ("string", "array"...) que empiezan con prefix.
+func Completions(kind string, prefix string) []Completion {
+	completions := []Completion{}
+	add := func(name string, signature *Signature) {
+		if !strings.HasPrefix(name, prefix) {
+			return
+		}
+		completion := Completion{Name: name}
+		if signature != nil {
+			completion.Signature = signature.String()
+			completion.Doc = signature.Doc
+		}
+		completions = append(completions, completion)
+	}
+	if kind == "" {
+		for name, function := range GlobalFx {
+			var signature *Signature
+			if native, ok := function.(NativeFunction); ok {
+				signature = native.Signature()
+			}
+			add(name, signature)
+		}
+	} else {
+		for name, method := range METHODS[kind] {
+			add(name, method.Signature)
+		}
+	}
+	sort.Slice(completions, func(a, b int) bool {
+		return completions[a].Name < completions[b].Name
+	})
+	return completions
+}
+
+func (c Completion) String() string {
+	line := c.Name + c.Signature
+	if c.Doc != "" {
+		line += "  " + c.Doc
+	}
+	return line
+}
+
+func init() {
+	GlobalFx["help"] = Help{}
+}
+
+type Help struct {
+}
+
+var helpSignature = sig("(topic?: any) -> string", "Describe a function, or list the methods of a type: help(\"string\").")
+
+// Call devuelve la ayuda de una funcion, de los metodos de un tipo o, sin
+// argumentos, de todas las funciones globales.
+func (c Help) Call(interpreter *Interpreter, arguments []interface{}, this interface{}) interface{} {
+	if len(arguments) == 0 {
+		return joinCompletions(Completions("", ""))
+	}
+	switch topic := arguments[0].(type) {
+	case string:
+		if _, ok := METHODS[topic]; ok {
+			return joinCompletions(Completions(topic, ""))
+		}
+		if function, ok := GlobalFx[topic]; ok {
+			return c.describe(topic, function)
+		}
+		message := fmt.Sprintf("No help for '%s'.", topic)
+		if hint := suggest(topic, append(interpreter.globals.Names(), methodKinds()...)); hint != "" {
+			message += " " + hint
+		}
+		return message
+	case Function:
+		return c.describe(topic.Name.Lexeme, topic)
+	case boundMethod:
+		return c.describe(topic.function.Name.Lexeme, topic.function)
+	case LoxCallable:
+		for name, function := range GlobalFx {
+			if interpreter.isEqual(function, topic) {
+				return c.describe(name, function)
+			}
+		}
+	}
+	return fmt.Sprintf("No help for %s.", typeName(arguments[0]))
+}
+
+func (c Help) describe(name string, function LoxCallable) string {
+	switch fn := function.(type) {
+	case NativeFunction:
+		return Completion{Name: name, Signature: fn.Signature().String(), Doc: fn.Signature().Doc}.String()
+	case Function:
+		params := make([]string, len(fn.Parameters))
+		for index, param := range fn.Parameters {
+			params[index] = param.Lexeme
+		}
+		return name + "(" + strings.Join(params, ", ") + ")"
+	}
+	return name
+}
+
+func (c Help) Arity() int {
+	return helpSignature.Arity()
+}
+
+func (c Help) Signature() *Signature {
+	return helpSignature
+}
+
+func joinCompletions(completions []Completion) string {
+	lines := make([]string, len(completions))
+	for index, completion := range completions {
+		lines[index] = completion.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+func methodKinds() []string {
+	kinds := []string{}
+	for kind := range METHODS {
+		kinds = append(kinds, kind)
+	}
+	return kinds
+}
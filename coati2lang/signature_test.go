@@ -0,0 +1,17 @@
+package coati2lang
+
+import "testing"
+
+func TestSignatureCheckConvertsNumbers(t *testing.T) {
+	arguments := []interface{}{int64(1), 2, 3.0}
+	sig("(start: number, end: number, step: number) -> array", "").check(Token{}, "f", arguments)
+	for index, argument := range arguments {
+		if _, ok := argument.(float64); !ok {
+			t.Errorf("argument %d is %T, want float64", index, argument)
+		}
+	}
+
+	if got := runSandboxed(t, "var result = [1, 2, 3].slice(clock() * 0, 2);", nil); got.(*LoxArray).String() != "[1 2]" {
+		t.Errorf("slice with clock() = %v, want [1 2]", got)
+	}
+}
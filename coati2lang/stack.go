@@ -0,0 +1,145 @@
+package coati2lang
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// Frame es una llamada de Lox en curso: el nombre de lo llamado y la linea
+// desde donde se llamo.
+type Frame struct {
+	Name string
+	Line int
+}
+
+// callFrame ejecuta call con un frame nuevo en la pila. Si la llamada
+// falla, el error se queda con una copia de la pila antes de desarmarla.
+func (i *Interpreter) callFrame(name string, site Token, call func() interface{}) interface{} {
+	depth := len(i.frames)
+	if i.MaxCallDepth > 0 && depth >= i.MaxCallDepth {
+		message := fmt.Sprintf("Stack overflow: more than %d nested calls.", i.MaxCallDepth)
+		if i.Strict {
+			message = "Stack overflow."
+		}
+		panic(&RuntimeError{
+			Token:   site,
+			Kind:    STACK_OVERFLOW,
+			Message: message,
+			Stack:   append([]Frame{}, i.frames...),
+		})
+	}
+	i.frames = append(i.frames, Frame{Name: name, Line: site.Line})
+	defer func() {
+		r := recover()
+		if r != nil {
+			r = i.withStack(r, i.frames)
+		}
+		i.frames = i.frames[:depth]
+		if r != nil {
+			panic(r)
+		}
+	}()
+	return call()
+}
+
+// withStack guarda la pila en el error la primera vez que pasa por un
+// frame. Los panics de control de flujo (return, break, generadores)
+// siguen de largo sin tocarse.
+func (i *Interpreter) withStack(r interface{}, frames []Frame) interface{} {
+	if _, ok := r.(runtime.Error); !ok {
+		if _, ok := r.(*RuntimeError); !ok {
+			return r
+		}
+	}
+	err := asRuntimeError(r)
+	if err.Stack == nil {
+		err.Stack = append([]Frame{}, frames...)
+	}
+	return err
+}
+
+// frameName elige el nombre que se muestra en la traza: el de la variable o
+// propiedad llamada, con la clase delante si es un metodo.
+func frameName(expr Call, callee interface{}) string {
+	name := "<anonymous>"
+	switch target := expr.Callee.(type) {
+	case Var:
+		name = target.Name.Lexeme
+	case Get:
+		name = target.Name.Lexeme
+	}
+	if method, ok := callee.(boundMethod); ok {
+		if instance, ok := method.this.(*LoxInstance); ok {
+			return instance.Class.Name + "." + method.function.Name.Lexeme
+		}
+	}
+	return name
+}
+
+// callableName es el nombre para la traza de las llamadas que no salen de
+// una expresion, como los callbacks de map o filter.
+func callableName(callable LoxCallable) string {
+	switch c := callable.(type) {
+	case Function:
+		// Las funciones anonimas tienen nombres internos (subfx-...).
+		if !strings.Contains(c.Name.Lexeme, "-") {
+			return c.Name.Lexeme
+		}
+	case boundMethod:
+		if instance, ok := c.this.(*LoxInstance); ok {
+			return instance.Class.Name + "." + c.function.Name.Lexeme
+		}
+		return c.function.Name.Lexeme
+	case *LoxClass:
+		return c.Name
+	}
+	return "<anonymous>"
+}
+
+// StackTrace devuelve la pila de Lox del error, de la llamada mas interna
+// hacia afuera, terminando en el script. Las lineas repetidas seguidas (la
+// recursion) se muestran una vez con la cantidad de repeticiones.
+func (e *RuntimeError) StackTrace() string {
+	var trace strings.Builder
+	for _, frame := range e.Frames() {
+		if frame.Line == 0 {
+			// Errores sin posicion, como los limites de ejecucion.
+			trace.WriteString("  at " + frame.Name + "\n")
+		} else {
+			fmt.Fprintf(&trace, "  at %s (line %d)\n", frame.Name, frame.Line)
+		}
+		if frame.Repeated > 0 {
+			fmt.Fprintf(&trace, "  ... repeated %d more times\n", frame.Repeated)
+		}
+	}
+	return trace.String()
+}
+
+// StackFrame es una linea de la traza: la funcion y la linea donde iba la
+// ejecucion dentro de ella. Repeated cuenta las repeticiones seguidas.
+type StackFrame struct {
+	Name     string `json:"name"`
+	Line     int    `json:"line,omitempty"`
+	Repeated int    `json:"repeated,omitempty"`
+}
+
+// Frames devuelve la traza de la llamada mas interna hacia afuera, con las
+// repeticiones seguidas (la recursion) juntas en un solo frame.
+func (e *RuntimeError) Frames() []StackFrame {
+	frames := []StackFrame{}
+	add := func(name string, line int) {
+		if last := len(frames) - 1; last >= 0 && frames[last].Name == name && frames[last].Line == line {
+			frames[last].Repeated++
+			return
+		}
+		frames = append(frames, StackFrame{Name: name, Line: line})
+	}
+	line := e.Token.Line
+	for index := len(e.Stack) - 1; index >= 0; index-- {
+		add(e.Stack[index].Name, line)
+		line = e.Stack[index].Line
+	}
+	add("<script>", line)
+	return frames
+}
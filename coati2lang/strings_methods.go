@@ -6,83 +6,122 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 var (
-	STRING_FX_MAP = map[string]func(string, ...any) interface{}{
-		"len":        len1,
-		"template":   template1,
-		"number":     number1,
-		"lower":      lower1,
-		"upper":      upper1,
-		"trim":       trim1,
-		"trimleft":   trimleft1,
-		"trimright":  trimright1,
-		"trimprefix": trimprefix1,
-		"trimsuffix": trimsuffix1,
-		"split":      split1,
+	STRING_FX_MAP = map[string]Method{
+		"len":        {Signature: sig("() -> number", "Number of characters."), Fx: len1},
+		"template":   {Signature: sig("() -> string", "Replaces ${name} placeholders with variables in scope."), Fx: template1},
+		"number":     {Signature: sig("() -> tuple", "Parses the string; returns (number, nil), or (nil, error) when it is not a number."), Fx: number1},
+		"lower":      {Signature: sig("() -> string", "Lowercase copy."), Fx: lower1},
+		"upper":      {Signature: sig("() -> string", "Uppercase copy."), Fx: upper1},
+		"trim":       {Signature: sig("() -> string", "Copy without leading and trailing whitespace."), Fx: trim1},
+		"trimleft":   {Signature: sig("(cutset: string) -> string", "Removes leading characters contained in cutset."), Fx: trimleft1},
+		"trimright":  {Signature: sig("(cutset: string) -> string", "Removes trailing characters contained in cutset."), Fx: trimright1},
+		"trimprefix": {Signature: sig("(prefix: string) -> string", "Removes prefix if present."), Fx: trimprefix1},
+		"trimsuffix": {Signature: sig("(suffix: string) -> string", "Removes suffix if present."), Fx: trimsuffix1},
+		"split":      {Signature: sig("(sep: string) -> array", "Splits around each sep."), Fx: split1},
+		"contains":   {Signature: sig("(substr: string) -> boolean", "Whether substr is in the string."), Fx: contains1},
+		"startswith": {Signature: sig("(prefix: string) -> boolean", "Whether the string starts with prefix."), Fx: startswith1},
+		"endswith":   {Signature: sig("(suffix: string) -> boolean", "Whether the string ends with suffix."), Fx: endswith1},
+		"replace":    {Signature: sig("(old: string, new: string) -> string", "Replaces every old with new."), Fx: replace1},
+		"index":      {Signature: sig("(substr: string) -> number", "Character index of substr, or -1."), Fx: index1},
+		"repeat":     {Signature: sig("(count: number) -> string", "The string repeated count times."), Fx: repeat1},
+		"chars":      {Signature: sig("() -> array", "Characters of the string."), Fx: chars1},
 	}
 )
 
-func split1(s string, args ...interface{}) interface{} {
-	interpreter := args[0].(*Interpreter)
-
-	arge := args[1].([]Expr)
-	argv := make([]interface{}, len(arge))
-	for i, arg := range arge {
-		argv[i] = arg.AcceptExpr(interpreter)
+func split1(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	parts := strings.Split(this.(string), args[0].(string))
+	values := make([]interface{}, len(parts))
+	for i, part := range parts {
+		interpreter.poll(i)
+		values[i] = part
 	}
-	p := argv[0].(string)
-	return strings.Split(s, p)
+	return NewLoxArray(values)
 }
 
-func lower1(s string, args ...interface{}) interface{} {
-	return strings.ToLower(s)
+func lower1(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	return strings.ToLower(this.(string))
 }
 
-func upper1(s string, args ...interface{}) interface{} {
-	return strings.ToUpper(s)
+func upper1(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	return strings.ToUpper(this.(string))
 }
 
-func trim1(s string, args ...interface{}) interface{} {
-	return strings.TrimSpace(s)
+func trim1(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	return strings.TrimSpace(this.(string))
 }
 
-func trimleft1(s string, args ...interface{}) interface{} {
-	return strings.TrimLeft(s, args[0].(string))
+func trimleft1(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	return strings.TrimLeft(this.(string), args[0].(string))
 }
 
-func trimright1(s string, args ...interface{}) interface{} {
-	return strings.TrimRight(s, args[0].(string))
+func trimright1(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	return strings.TrimRight(this.(string), args[0].(string))
 }
 
-func trimprefix1(s string, args ...interface{}) interface{} {
-	return strings.TrimPrefix(s, args[0].(string))
+func trimprefix1(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	return strings.TrimPrefix(this.(string), args[0].(string))
 }
 
-func trimsuffix1(s string, args ...interface{}) interface{} {
-	return strings.TrimSuffix(s, args[0].(string))
+func trimsuffix1(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	return strings.TrimSuffix(this.(string), args[0].(string))
 }
 
-func len1(s string, args ...interface{}) interface{} {
-	return len(s)
+func contains1(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	return strings.Contains(this.(string), args[0].(string))
 }
 
-func number1(s string, args ...interface{}) interface{} {
-	f, err := strconv.ParseFloat(s, 64)
-	if err != nil {
-		return err
+func startswith1(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	return strings.HasPrefix(this.(string), args[0].(string))
+}
+
+func endswith1(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	return strings.HasSuffix(this.(string), args[0].(string))
+}
+
+func replace1(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	return strings.ReplaceAll(this.(string), args[0].(string), args[1].(string))
+}
+
+func index1(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	text := this.(string)
+	index := strings.Index(text, args[0].(string))
+	if index < 0 {
+		return float64(index)
 	}
-	return f
+	return float64(utf8.RuneCountInString(text[:index]))
 }
 
-func template1(s string, args ...interface{}) interface{} {
-	if len(args) == 0 {
-		return s
+func chars1(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	chars := []interface{}{}
+	for index, char := range this.(string) {
+		interpreter.poll(index)
+		chars = append(chars, string(char))
 	}
-	interpreter := args[0].(*Interpreter)
+	return NewLoxArray(chars)
+}
+
+func repeat1(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	return interpreter.repeat(Token{}, this.(string), args[0].(float64))
+}
+
+func len1(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	return float64(utf8.RuneCountInString(this.(string)))
+}
+
+func number1(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	f, err := strconv.ParseFloat(this.(string), 64)
+	if err != nil {
+		return Values(nil, fmt.Sprintf("'%s' is not a number.", this))
+	}
+	return Values(f, nil)
+}
 
-	result, err := replaceNestedKeys(s, interpreter)
+func template1(interpreter *Interpreter, this interface{}, args []interface{}) interface{} {
+	result, err := replaceNestedKeys(this.(string), interpreter)
 	if err != nil {
 		return err
 	}
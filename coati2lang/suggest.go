@@ -0,0 +1,125 @@
+package coati2lang
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// suggest busca entre candidates el nombre mas parecido a name y devuelve
+// el hint "Did you mean 'x'?", o "" si ninguno esta lo bastante cerca.
+func suggest(name string, candidates []string) string {
+	limit := len(name) / 3
+	if limit < 1 {
+		limit = 1
+	}
+	if limit > 3 {
+		limit = 3
+	}
+
+	if limit >= len([]rune(name)) {
+		// Con nombres de una o dos letras cualquier cosa queda cerca.
+		limit = len([]rune(name)) - 1
+	}
+
+	sort.Strings(candidates)
+	best, bestDistance := "", limit+1
+	for _, candidate := range candidates {
+		// Los nombres internos del parser (subfx-..., submap-...) no se sugieren.
+		if candidate == name || strings.Contains(candidate, "-") {
+			continue
+		}
+		distance := editDistance(strings.ToLower(name), strings.ToLower(candidate))
+		if distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf("Did you mean '%s'?", best)
+}
+
+// editDistance es la distancia de Damerau-Levenshtein restringida: cuenta
+// una transposicion de letras vecinas (lenght/length) como un solo cambio.
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	rows := make([][]int, len(s)+1)
+	for x := range rows {
+		rows[x] = make([]int, len(t)+1)
+		rows[x][0] = x
+	}
+	for y := range rows[0] {
+		rows[0][y] = y
+	}
+	for x := 1; x <= len(s); x++ {
+		for y := 1; y <= len(t); y++ {
+			cost := 1
+			if s[x-1] == t[y-1] {
+				cost = 0
+			}
+			rows[x][y] = minInt(rows[x-1][y]+1, minInt(rows[x][y-1]+1, rows[x-1][y-1]+cost))
+			if x > 1 && y > 1 && s[x-1] == t[y-2] && s[x-2] == t[y-1] {
+				rows[x][y] = minInt(rows[x][y], rows[x-2][y-2]+1)
+			}
+		}
+	}
+	return rows[len(s)][len(t)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Names devuelve los nombres visibles desde este scope, sin repetir.
+func (e *Enviroment) Names() []string {
+	seen := make(map[string]bool)
+	names := []string{}
+	for enviroment := e; enviroment != nil; enviroment = enviroment.Enclosing {
+		for name := range enviroment.Values {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// methodNames son los metodos nativos y las extensiones de un tipo.
+func (i *Interpreter) methodNames(kind string) []string {
+	names := []string{}
+	for name := range METHODS[kind] {
+		names = append(names, name)
+	}
+	for name := range i.extensions[kind] {
+		names = append(names, name)
+	}
+	return names
+}
+
+// propertyNames son los campos y metodos de una instancia.
+func propertyNames(object interface{}) []string {
+	names := []string{}
+	instance, ok := object.(*LoxInstance)
+	if !ok {
+		return names
+	}
+	for name := range instance.Fields {
+		names = append(names, name)
+	}
+	return append(names, instance.Class.methodNames()...)
+}
+
+func (c *LoxClass) methodNames() []string {
+	names := []string{}
+	for class := c; class != nil; class = class.Superclass {
+		for name := range class.Methods {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -28,23 +28,31 @@ const (
 	AMPERSAND                      //[] &
 
 	// One or two character tokens.
-	BANG          //[ok] !
-	BANG_EQUAL    //[ok] !=
-	EQUAL         //[ok] =
-	EQUAL_EQUAL   //[ok] ==
-	GREATER       //[ok] >
-	GREATER_EQUAL //[ok] >=
-	LESS          //[ok] <
-	LESS_EQUAL    //[ok] <=
-	PLUS_PLUS     //[ok] ++
-	MINUS_MINUS   //[ok] --
-	STAR_STAR     //[ok] **
-	ARROW         //[] ->
-	LEFT          //[] <<
-	RIGHT         //[] >>
-	PIPE          //[OK] ABS
-	OR_OR         //[] ||
-	AND_AND       //[] &&
+	BANG             //[ok] !
+	BANG_EQUAL       //[ok] !=
+	EQUAL            //[ok] =
+	EQUAL_EQUAL      //[ok] ==
+	GREATER          //[ok] >
+	GREATER_EQUAL    //[ok] >=
+	LESS             //[ok] <
+	LESS_EQUAL       //[ok] <=
+	PLUS_PLUS        //[ok] ++
+	MINUS_MINUS      //[ok] --
+	STAR_STAR        //[ok] **
+	ARROW            //[] ->
+	LEFT             //[] <<
+	RIGHT            //[] >>
+	PIPE             //[OK] ABS
+	OR_OR            //[] ||
+	AND_AND          //[] &&
+	QUESTION_DOT     //[ok] ?.
+	QUESTION_BRACKET //[ok] ?[
+	HASH_BRACE       //[ok] #{
+	PLUS_EQUAL       //[ok] +=
+	MINUS_EQUAL      //[ok] -=
+	STAR_EQUAL       //[ok] *=
+	SLASH_EQUAL      //[ok] /=
+	STAR_STAR_EQUAL  //[ok] **=
 
 	// Literals.
 	IDENTIFIER       //[ok]
@@ -64,7 +72,7 @@ const (
 	THROW      //[]
 	ADD        //[]
 	DELETE     //[]
-	TYPEOF     //[]
+	TYPEOF     //[ok]
 	INSTANCEOF //[]
 	EXTENDS    //[]
 	SWITCH     //[]
@@ -88,6 +96,11 @@ const (
 	FOR      //[ok]
 	BREAK    //[]
 	CONTINUE //[]
+	YIELD    //[ok]
+	IN       //[ok]
+	ENUM     //[ok]
+	MATCH    //[ok]
+	EXTEND   //[ok]
 
 	EOF
 )
@@ -97,6 +110,7 @@ type Token struct {
 	Lexeme  string
 	Literal interface{}
 	Line    int
+	Column  int
 }
 
 func NewToken(tokenType TokenType, lexeme string, literal interface{}, line int) Token {
@@ -112,11 +126,9 @@ func (t Token) String() string {
 	return fmt.Sprintf("%v %s %v", t.Type, t.Lexeme, t.Literal)
 }
 
-func ScanTokens(source string) []Token {
-	// Implementación de tu escaneo de tokens aquí
-	var tokens []Token
+func ScanTokens(source string) ([]Token, []error) {
 	scanner := NewScanner(source)
-	tokens = scanner.ScanTokens()
+	tokens := scanner.ScanTokens()
 
-	return tokens
+	return tokens, scanner.Errors
 }
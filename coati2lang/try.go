@@ -0,0 +1,75 @@
+package coati2lang
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+func (i *Interpreter) VisitThrowStmt(stmt Throw) interface{} {
+	value := i.full_evaluate(stmt.Value)
+	message := fmt.Sprint(value)
+	if object, ok := thaw(value).(map[interface{}]interface{}); ok {
+		if text, ok := object["message"].(string); ok {
+			message = text
+		}
+	}
+	panic(&RuntimeError{Token: stmt.Keyword, Kind: THROWN_ERROR, Message: message, Value: value})
+}
+
+func (i *Interpreter) VisitTryStmt(stmt Try) interface{} {
+	if stmt.Finally != nil {
+		defer i.executeBlock(stmt.Finally, *NewEnviroment(i.enviroment))
+	}
+
+	err := i.tryBlock(stmt.Body)
+	if err == nil || stmt.Catch == nil {
+		if err != nil {
+			panic(err)
+		}
+		return nil
+	}
+
+	enviroment := NewEnviroment(i.enviroment)
+	if stmt.CatchName != nil {
+		enviroment.Define(stmt.CatchName.Lexeme, errorValue(err))
+	}
+	return i.executeBlock(stmt.Catch, *enviroment)
+}
+
+// tryBlock ejecuta el cuerpo del try y devuelve el error de Lox que lo
+// corto, si hubo. Return, break y continue no son errores y siguen de largo.
+func (i *Interpreter) tryBlock(body []Stmt) (err *RuntimeError) {
+	depth := len(i.frames)
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		if _, ok := r.(runtime.Error); !ok {
+			if _, ok := r.(*RuntimeError); !ok {
+				panic(r)
+			}
+		}
+		err = i.withStack(r, i.frames).(*RuntimeError)
+		if isBudgetError(err) {
+			panic(err)
+		}
+		i.frames = i.frames[:depth]
+	}()
+	i.executeBlock(body, *NewEnviroment(i.enviroment))
+	return nil
+}
+
+// errorValue es lo que ve el bloque catch: un mapa con el mensaje, el tipo
+// de error, la linea, el valor lanzado, la sugerencia y la pila como texto.
+func errorValue(err *RuntimeError) map[interface{}]interface{} {
+	return map[interface{}]interface{}{
+		"message": err.Message,
+		"hint":    err.Hint,
+		"kind":    string(err.Kind),
+		"line":    float64(err.Token.Line),
+		"value":   err.Value,
+		"stack":   strings.TrimRight(err.StackTrace(), "\n"),
+	}
+}
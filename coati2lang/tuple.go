@@ -0,0 +1,142 @@
+package coati2lang
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+func init() {
+	GlobalFx["freeze"] = Freeze{}
+}
+
+// Tuple es una secuencia inmutable: (1, 2, 3) o los valores de return a, b;
+type Tuple []interface{}
+
+// FrozenMap es un mapa congelado con freeze(); se lee igual que un mapa.
+type FrozenMap map[interface{}]interface{}
+
+var (
+	errTupleAssign  = errors.New("can't assign to an element of an immutable tuple")
+	errFrozenAssign = errors.New("can't assign to a key of a frozen map")
+)
+
+func (t Tuple) String() string {
+	items := make([]string, len(t))
+	for index, item := range t {
+		items[index] = fmt.Sprint(item)
+	}
+	if len(t) == 1 {
+		return "(" + items[0] + ",)"
+	}
+	return "(" + strings.Join(items, ", ") + ")"
+}
+
+// Values permite a una funcion nativa devolver varios valores, igual que
+// return a, b; en el script.
+func Values(values ...interface{}) interface{} {
+	return Tuple(values)
+}
+
+// spread reparte un valor multiple entre count nombres. Un valor simple va
+// al primero y el resto queda en nil.
+func spread(value interface{}, count int) []interface{} {
+	values := make([]interface{}, count)
+	items, ok := value.(Tuple)
+	if !ok {
+		values[0] = value
+		return values
+	}
+	copy(values, items)
+	return values
+}
+
+// thaw devuelve la vista de solo lectura de un valor inmutable como array o
+// mapa comun, para reutilizar la logica de lectura.
+func thaw(value interface{}) interface{} {
+	switch v := value.(type) {
+	case Tuple:
+		return []interface{}(v)
+	case *LoxArray:
+		return v.Elements
+	case FrozenMap:
+		return map[interface{}]interface{}(v)
+	}
+	return value
+}
+
+func isFrozen(value interface{}) bool {
+	switch v := value.(type) {
+	case Tuple, FrozenMap:
+		return true
+	case *LoxSet:
+		return v.frozen
+	}
+	return false
+}
+
+func freezeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *LoxArray:
+		tuple := make(Tuple, len(v.Elements))
+		for index, item := range v.Elements {
+			tuple[index] = freezeValue(item)
+		}
+		return tuple
+	case map[interface{}]interface{}:
+		frozen := make(FrozenMap, len(v))
+		for key, item := range v {
+			frozen[key] = freezeValue(item)
+		}
+		return frozen
+	case *LoxSet:
+		frozen := v.Clone()
+		frozen.frozen = true
+		return frozen
+	}
+	return value
+}
+
+var freezeSignature = sig("(value: any) -> any", "Deeply frozen copy of arrays, maps and sets.")
+
+type Freeze struct {
+}
+
+// Call devuelve una copia congelada del valor; arrays, mapas y sets
+// anidados tambien quedan congelados.
+func (c Freeze) Call(interpreter *Interpreter, arguments []interface{}, this interface{}) interface{} {
+	return freezeValue(arguments[0])
+}
+
+func (c Freeze) Arity() int {
+	return freezeSignature.Arity()
+}
+
+func (c Freeze) Signature() *Signature {
+	return freezeSignature
+}
+
+func (i *Interpreter) VisitTupleExpr(expr TupleLiteral) interface{} {
+	tuple := make(Tuple, len(expr.Elements))
+	for index, element := range expr.Elements {
+		tuple[index] = i.full_evaluate(element)
+	}
+	return i.charge(expr.Paren, tuple)
+}
+
+func (i *Interpreter) VisitMultiVarStmt(stmt MultiVar) interface{} {
+	values := spread(i.full_evaluate(stmt.Value), len(stmt.Names))
+	for index, name := range stmt.Names {
+		i.enviroment.Define(name.Lexeme, values[index])
+	}
+	return nil
+}
+
+func (i *Interpreter) VisitMultiAssignExpr(expr MultiAssign) interface{} {
+	value := i.full_evaluate(expr.Value)
+	values := spread(value, len(expr.Targets))
+	for index, target := range expr.Targets {
+		i.assignTo(target, values[index])
+	}
+	return value
+}
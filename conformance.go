@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	expectOutput       = regexp.MustCompile(`// expect: ?(.*)`)
+	expectRuntimeError = regexp.MustCompile(`// expect runtime error: (.+)`)
+	expectSyntaxError  = regexp.MustCompile(`// (\[line (\d+)\] )?(Error.*)`)
+)
+
+// loxTest son las expectativas de un archivo de la suite, escritas con las
+// mismas anotaciones que usa el libro.
+type loxTest struct {
+	path         string
+	output       []string
+	errors       []string
+	runtimeError string
+}
+
+func parseLoxTest(path string) (*loxTest, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	test := &loxTest{path: path}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if match := expectOutput.FindStringSubmatch(text); match != nil {
+			test.output = append(test.output, match[1])
+		} else if match := expectRuntimeError.FindStringSubmatch(text); match != nil {
+			test.runtimeError = match[1]
+		} else if match := expectSyntaxError.FindStringSubmatch(text); match != nil {
+			errorLine := match[2]
+			if errorLine == "" {
+				errorLine = fmt.Sprint(line)
+			}
+			test.errors = append(test.errors, fmt.Sprintf("[line %s] %s", errorLine, match[3]))
+		}
+	}
+	return test, scanner.Err()
+}
+
+// run ejecuta el archivo en un proceso aparte: los errores en tiempo de
+// ejecucion terminan el interprete y no deben cortar la suite.
+func (t *loxTest) run() []string {
+	executable, err := os.Executable()
+	if err != nil {
+		return []string{err.Error()}
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(executable, "-lox-strict", "-script", t.path)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	runErr := cmd.Run()
+
+	failures := []string{}
+	output := strings.Split(strings.TrimRight(stdout.String(), "\n"), "\n")
+	if stdout.Len() == 0 {
+		output = []string{}
+	}
+	for index, expected := range t.output {
+		if index >= len(output) {
+			failures = append(failures, fmt.Sprintf("missing expected output '%s'", expected))
+			continue
+		}
+		if output[index] != expected {
+			failures = append(failures, fmt.Sprintf("expected output '%s' but got '%s'", expected, output[index]))
+		}
+	}
+	if len(output) > len(t.output) {
+		for _, extra := range output[len(t.output):] {
+			failures = append(failures, fmt.Sprintf("unexpected output '%s'", extra))
+		}
+	}
+
+	for _, expected := range t.errors {
+		if !strings.Contains(stderr.String(), expected) {
+			failures = append(failures, fmt.Sprintf("missing expected error '%s'", expected))
+		}
+	}
+	if t.runtimeError != "" && !strings.Contains(stderr.String(), t.runtimeError) {
+		failures = append(failures, fmt.Sprintf("missing expected runtime error '%s'", t.runtimeError))
+	}
+	if runErr == nil && (t.runtimeError != "" || len(t.errors) > 0) {
+		failures = append(failures, "expected the interpreter to fail")
+	}
+	if runErr != nil && t.runtimeError == "" && len(t.errors) == 0 {
+		failures = append(failures, fmt.Sprintf("unexpected failure: %s", strings.TrimSpace(stderr.String())))
+	}
+	return failures
+}
+
+// runConformance corre todos los .lox de dir y reporta PASS/FAIL por archivo.
+func runConformance(dir string) bool {
+	paths := []string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && strings.HasSuffix(path, ".lox") {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return false
+	}
+	sort.Strings(paths)
+
+	passed := 0
+	for _, path := range paths {
+		test, err := parseLoxTest(path)
+		if err != nil {
+			fmt.Printf("FAIL %s\n\t%s\n", path, err)
+			continue
+		}
+		failures := test.run()
+		if len(failures) > 0 {
+			fmt.Printf("FAIL %s\n", path)
+			for _, failure := range failures {
+				fmt.Printf("\t%s\n", failure)
+			}
+			continue
+		}
+		passed++
+		fmt.Printf("PASS %s\n", path)
+	}
+
+	fmt.Printf("\n%d passed, %d failed, %d total\n", passed, len(paths)-passed, len(paths))
+	return passed == len(paths)
+}
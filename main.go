@@ -1,9 +1,12 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/arturoeanton/go-r2lox/coati2lang"
 )
@@ -17,20 +20,151 @@ func runFile(path string) (string, error) {
 	return contenido, nil
 }
 
-func run(source string) {
-	tokens := coati2lang.ScanTokens(source)
+// report muestra los errores en el formato de -diagnostics y termina con code.
+func report(file, format string, errs []error, code int) {
+	diagnostics := make([]coati2lang.Diagnostic, len(errs))
+	for index, err := range errs {
+		diagnostics[index] = coati2lang.NewDiagnostic(file, err)
+	}
+	if err := coati2lang.WriteDiagnostics(os.Stderr, format, diagnostics); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+	}
+	os.Exit(code)
+}
+
+// limits son los limites de ejecucion que se pasan por linea de comandos.
+type limits struct {
+	maxCallDepth  int
+	maxSteps      int64
+	maxAllocation int64
+	timeout       time.Duration
+	allow         string
+	audit         bool
+}
+
+func run(file, source string, strict bool, format string, limits limits) {
+	scanner := coati2lang.NewScanner(source)
+	scanner.Strict = strict
+	tokens, errs := scanner.ScanTokens(), scanner.Errors
 	parse := coati2lang.NewParser(tokens)
+	parse.Strict = strict
 
-	expr := parse.Parse()
+	expr, parseErrs := parse.Parse()
+	if errs = append(errs, parseErrs...); len(errs) > 0 {
+		report(file, format, errs, coati2lang.ERROR_SYNTAX)
+	}
 	interp := coati2lang.NewInterpreter(expr)
-	interp.Interpret()
+	interp.Strict = strict
+	interp.MaxCallDepth = limits.maxCallDepth
+	interp.MaxSteps = limits.maxSteps
+	interp.MaxAllocation = limits.maxAllocation
+	if limits.allow != "" {
+		capabilities, err := coati2lang.ParseCapabilities(limits.allow)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(2)
+		}
+		interp.Capabilities = capabilities
+	}
+	if limits.audit {
+		interp.AuditHook = func(event coati2lang.AuditEvent) {
+			fmt.Fprintln(os.Stderr, "audit:", event)
+		}
+	}
+
+	resolver := coati2lang.NewResolver(interp)
+	if errs := resolver.Resolve(expr); len(errs) > 0 {
+		report(file, format, errs, coati2lang.ERROR_RESOLVE)
+	}
+	ctx := context.Background()
+	if limits.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, limits.timeout)
+		defer cancel()
+	}
+	if _, err := interp.InterpretContext(ctx); err != nil {
+		report(file, format, []error{err}, coati2lang.ERROR_RUNTIME)
+	}
+}
 
+// checkFormat termina con 2 si -diagnostics no es un formato conocido.
+func checkFormat(format string) {
+	switch format {
+	case "text", "json", "sarif":
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown diagnostics format '%s'\n", format)
+		os.Exit(2)
+	}
+}
+
+// lint es el subcomando r2lox lint [flags] archivos...; las advertencias de
+// todos los archivos salen juntas por stdout.
+func lint(args []string) int {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	strict := flags.Bool("lox-strict", false, "parse with standard Lox syntax")
+	format := flags.String("diagnostics", "text", "output format: text, json or sarif")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s lint [flags] file.lox...\nRules: %s\n", os.Args[0], strings.Join(coati2lang.LintRules, ", "))
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	checkFormat(*format)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	code := 0
+	diagnostics := []coati2lang.Diagnostic{}
+	for _, file := range flags.Args() {
+		source, err := runFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return coati2lang.ERROR_FILE_NOT_FOUND
+		}
+		warnings, errs := coati2lang.Lint(file, source, *strict)
+		for _, err := range errs {
+			diagnostics = append(diagnostics, coati2lang.NewDiagnostic(file, err))
+			code = coati2lang.ERROR_SYNTAX
+		}
+		if len(warnings) > 0 && code == 0 {
+			code = coati2lang.ERROR_LINT
+		}
+		diagnostics = append(diagnostics, warnings...)
+	}
+	if err := coati2lang.WriteDiagnostics(os.Stdout, *format, diagnostics); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+	}
+	return code
 }
 
 func main() {
-	var arg_script string
+	if len(os.Args) > 1 && os.Args[1] == "lint" {
+		os.Exit(lint(os.Args[2:]))
+	}
+
+	var arg_script, arg_lox_tests, arg_diagnostics string
+	var arg_lox_strict bool
+	var arg_limits limits
 	flag.StringVar(&arg_script, "script", "script.lox", "script to run")
+	flag.BoolVar(&arg_lox_strict, "lox-strict", false, "run with standard Lox syntax and semantics")
+	flag.StringVar(&arg_lox_tests, "lox-tests", "", "run the Lox conformance suite in this directory")
+	flag.IntVar(&arg_limits.maxCallDepth, "max-call-depth", coati2lang.MAX_CALL_DEPTH, "maximum nested calls before a stack overflow error (0 = no limit)")
+	flag.Int64Var(&arg_limits.maxSteps, "max-steps", 0, "maximum evaluated statements and expressions (0 = no limit)")
+	flag.Int64Var(&arg_limits.maxAllocation, "max-alloc", 0, "approximate maximum bytes allocated for strings, arrays, maps and sets (0 = no limit)")
+	flag.StringVar(&arg_limits.allow, "allow", "", "capabilities for readfile, writefile and getenv, e.g. fs:read:/data,os:env (all = no restrictions; default: none)")
+	flag.BoolVar(&arg_limits.audit, "audit", false, "log every use of privileged natives to stderr")
+	flag.DurationVar(&arg_limits.timeout, "timeout", 0, "cancel the script after this duration, e.g. 2s (0 = no limit)")
+	flag.StringVar(&arg_diagnostics, "diagnostics", "text", "error output format: text, json or sarif")
 	flag.Parse()
+	checkFormat(arg_diagnostics)
+
+	if arg_lox_tests != "" {
+		if !runConformance(arg_lox_tests) {
+			os.Exit(1)
+		}
+		return
+	}
 
 	source, err := runFile(arg_script)
 
@@ -38,9 +172,5 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
 		os.Exit(coati2lang.ERROR_FILE_NOT_FOUND)
 	}
-	run(source)
-	if coati2lang.HasError {
-		os.Exit(coati2lang.ERROR_SYNTAX)
-	}
-
+	run(arg_script, source, arg_lox_strict, arg_diagnostics, arg_limits)
 }